@@ -0,0 +1,71 @@
+package benchmarks
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	M "github.com/AhmedGoudaa/go_memoize"
+)
+
+func slowLookup(key string) string {
+	if key != "hot" {
+		time.Sleep(2 * time.Millisecond)
+	}
+	return key
+}
+
+// startSlowMisses keeps the given number of goroutines calling fn with unique keys until the returned stop function is called.
+func startSlowMisses(fn func(string) string, goroutines int) (stop func()) {
+	var (
+		wg   sync.WaitGroup
+		next atomic.Int64
+		done = make(chan struct{})
+	)
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					fn("miss-" + strconv.FormatInt(next.Add(1), 10))
+				}
+			}
+		}()
+	}
+	return func() {
+		close(done)
+		wg.Wait()
+	}
+}
+
+func BenchmarkHitParallel(b *testing.B) {
+	memoized := M.Memoize1(slowLookup, 10*time.Minute)
+	memoized("hot")
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			memoized("hot")
+		}
+	})
+}
+
+func BenchmarkHitParallelWithSlowMisses(b *testing.B) {
+	memoized := M.Memoize1(slowLookup, 10*time.Minute)
+	memoized("hot")
+	stop := startSlowMisses(memoized, 8)
+	defer stop()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			memoized("hot")
+		}
+	})
+}
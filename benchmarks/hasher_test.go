@@ -0,0 +1,86 @@
+package benchmarks
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	M "github.com/AhmedGoudaa/go_memoize"
+)
+
+// hashers are the hashers compared by the benchmarks below.
+// The caches are split into shards, as keys are only hashed to select a shard or for the eviction policy.
+var hashers = []struct {
+	name   string
+	hasher M.Hasher
+}{
+	{"FNV1a", M.FNV1a},
+	{"MapHasher", M.NewMapHasher()},
+	{"XXHashStyle", M.XXHashStyle},
+}
+
+func BenchmarkHasherDo1Mem(b *testing.B) {
+	for _, h := range hashers {
+		b.Run(h.name, func(b *testing.B) {
+			DoSomThing1Memoized := M.Memoize1(DoSomThing1, 10*time.Minute, M.WithShards(16), M.WithHasher(h.hasher))
+
+			params := []string{"1111", "2222", "3333", "4444"}
+			for i := 0; i < b.N; i++ {
+				DoSomThing1Memoized(params[i%4])
+			}
+		})
+	}
+}
+
+func BenchmarkHasherDo3Mem(b *testing.B) {
+	for _, h := range hashers {
+		b.Run(h.name, func(b *testing.B) {
+			DoSomThing3Memoized := M.Memoize3(DoSomThing3, 10*time.Minute, M.WithShards(16), M.WithHasher(h.hasher))
+
+			params := []struct {
+				a, b, c string
+			}{
+				{"1111", "2222", "3333"},
+				{"4444", "5555", "6666"},
+				{"7777", "8888", "9999"},
+				{"aaaa", "bbbb", "cccc"},
+			}
+			for i := 0; i < b.N; i++ {
+				DoSomThing3Memoized(params[i%4].a, params[i%4].b, params[i%4].c)
+			}
+		})
+	}
+}
+
+func BenchmarkHasherDo4Mem(b *testing.B) {
+	for _, h := range hashers {
+		b.Run(h.name, func(b *testing.B) {
+			DoSomThing4Memoized := M.Memoize4(DoSomThing4, 10*time.Minute, M.WithShards(16), M.WithHasher(h.hasher))
+			params := []struct {
+				a, b, c string
+				s       int
+			}{
+				{"1111", "2222", "3333", 1},
+				{"4444", "5555", "6666", 2},
+				{"7777", "8888", "9999", 3},
+				{"aaaa", "bbbb", "cccc", 4},
+			}
+			for i := 0; i < b.N; i++ {
+				DoSomThing4Memoized(params[i%4].a, params[i%4].b, params[i%4].c, params[i%4].s)
+			}
+		})
+	}
+}
+
+func BenchmarkHasherLongStringMem(b *testing.B) {
+	for _, h := range hashers {
+		b.Run(h.name, func(b *testing.B) {
+			DoSomThing1Memoized := M.Memoize1(DoSomThing1, 10*time.Minute, M.WithShards(16), M.WithHasher(h.hasher))
+
+			params := []string{strings.Repeat("1", 1024), strings.Repeat("2", 1024), strings.Repeat("3", 1024), strings.Repeat("4", 1024)}
+			for i := 0; i < b.N; i++ {
+				DoSomThing1Memoized(params[i%4])
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package benchmarks
+
+import (
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	M "github.com/AhmedGoudaa/go_memoize"
+)
+
+func benchmarkShardedHits(b *testing.B, opts ...M.Option) {
+	memoized := M.Memoize1(DoSomThing1, 10*time.Minute, opts...)
+	params := make([]string, 1024)
+	for i := range params {
+		params[i] = "key-" + strconv.Itoa(i)
+		memoized(params[i])
+	}
+	var seed atomic.Int64
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := int(seed.Add(7919))
+		for pb.Next() {
+			memoized(params[i%len(params)])
+			i++
+		}
+	})
+}
+
+func BenchmarkHitParallelSingleShard(b *testing.B) {
+	benchmarkShardedHits(b)
+}
+
+func BenchmarkHitParallel16Shards(b *testing.B) {
+	benchmarkShardedHits(b, M.WithShards(16))
+}
+
+func BenchmarkHitParallel64Shards(b *testing.B) {
+	benchmarkShardedHits(b, M.WithShards(64))
+}
@@ -1,6 +1,8 @@
 package go_memoize
 
 import (
+	"context"
+	"errors"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -12,126 +14,905 @@ func zeroValue[T any]() T {
 	return zero
 }
 
+const (
+	// minTickInterval and maxTickInterval bound the interval at which the cache group refreshes its clock.
+	minTickInterval = time.Millisecond
+	maxTickInterval = time.Second
+	// tickResolution is the number of ticks per smallest TTL, bounding the error on expiry to a fraction of the TTL.
+	tickResolution = 16
+	// idleTicks is the number of ticks without a read of the clock after which the ticker pauses.
+	idleTicks = 64
+	// sweepInterval is the interval at which the janitor sweeps expired entries.
+	sweepInterval = time.Second
+)
+
 // cacheGroup manages multiple caches with a shared ticker.
+// The ticker refreshes the cached clock, checks for entries due for refresh-ahead and periodically runs the janitor
+// sweeping expired entries, see janitor.go.
+// It only runs while caches use the group: it starts with the first cache and stops once all caches are closed
+// or garbage collected, or on Shutdown.
+//
+// The clock ticks at an interval derived from the smallest TTL of the caches using it, so that it is precise enough for
+// short TTLs without waking up needlessly for long ones. While nothing reads it, the clock pauses until the next sweep:
+// readers then read the time directly and wake the ticker up.
 type cacheGroup struct {
-	now          atomic.Value
-	ticker       *time.Ticker
-	tickInterval time.Duration
-	done         chan struct{}
+	now          atomic.Int64 // monotonic time in nanoseconds, refreshed every tick, or 0 while the ticker is paused or stopped
+	read         atomic.Bool  // whether the clock was read since the last tick
+	wake         chan struct{}
+	tickInterval atomic.Int64 // in nanoseconds
+
+	mu       sync.Mutex
+	refs     int           // number of caches using the group
+	ttls     map[int64]int // number of caches using the group by TTL
+	done     chan struct{} // closed to stop the ticker, nil while it is stopped
+	stopped  chan struct{} // closed once the ticker goroutine returned
+	sweepers map[*sweeper]struct{}
+	// refreshers are the sweepers refreshing entries ahead of expiry, run on every tick, see refreshAhead.
+	// The slice is replaced rather than modified under mu, so that the ticker reads it without locking.
+	refreshers atomic.Pointer[[]*sweeper]
 }
 
-// newCacheGroup creates a new cache group with a shared ticker.
+// newCacheGroup creates a new cache group with a shared ticker, started by the first cache using it.
 func newCacheGroup() *cacheGroup {
-	group := &cacheGroup{
-		done:         make(chan struct{}),
-		tickInterval: time.Millisecond,
+	g := &cacheGroup{
+		wake:     make(chan struct{}, 1),
+		ttls:     make(map[int64]int),
+		sweepers: make(map[*sweeper]struct{}),
+	}
+	g.tickInterval.Store(int64(maxTickInterval))
+	return g
+}
+
+// acquire registers a cache using the group, with the TTL its clock must be precise enough for, or 0 if none.
+// It starts the ticker if it is stopped.
+func (g *cacheGroup) acquire(ttl int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.refs++
+	if ttl > 0 {
+		g.ttls[ttl]++
+		g.updateTickInterval()
+	}
+	if g.done == nil {
+		g.startTicker()
+	}
+}
+
+// release unregisters a cache added by acquire with the same TTL, stopping the ticker once no cache uses the group.
+// It does not wait for the ticker goroutine to return, as it may be called by the janitor itself.
+func (g *cacheGroup) release(ttl int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if ttl > 0 {
+		if g.ttls[ttl]--; g.ttls[ttl] == 0 {
+			delete(g.ttls, ttl)
+		}
+		g.updateTickInterval()
+	}
+	if g.refs--; g.refs == 0 && g.done != nil {
+		g.stopTicker()
+	}
+}
+
+// retune replaces the TTL a cache acquired the group with, see acquire.
+func (g *cacheGroup) retune(old, ttl int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if old > 0 {
+		if g.ttls[old]--; g.ttls[old] == 0 {
+			delete(g.ttls, old)
+		}
+	}
+	g.ttls[ttl]++
+	g.updateTickInterval()
+}
+
+// updateTickInterval derives the tick interval from the smallest TTL of the caches using the group.
+// The group lock must be held.
+func (g *cacheGroup) updateTickInterval() {
+	interval := maxTickInterval
+	for ttl := range g.ttls {
+		interval = min(interval, tickIntervalFor(ttl))
+	}
+	if old := g.tickInterval.Swap(int64(interval)); int64(interval) < old {
+		// the ticker may be waiting for the longer interval
+		g.wakeUp()
+	}
+}
+
+// tickIntervalFor returns the tick interval keeping the error on the expiry of entries with the given TTL
+// within a tickResolution-th of it, or maxTickInterval for entries that never expire.
+func tickIntervalFor(ttl int64) time.Duration {
+	if ttl <= 0 {
+		return maxTickInterval
+	}
+	return max(min(time.Duration(ttl)/tickResolution, maxTickInterval), minTickInterval)
+}
+
+// wakeUp makes the ticker refresh the clock now and resume ticking if it is paused.
+func (g *cacheGroup) wakeUp() {
+	select {
+	case g.wake <- struct{}{}:
+	default:
 	}
-	group.now.Store(time.Now().Unix())
-	group.startTicker()
-	return group
 }
 
-// startTicker starts the ticker for the cache group.
+// startTicker starts the ticker for the cache group. The group lock must be held.
 func (g *cacheGroup) startTicker() {
-	g.ticker = time.NewTicker(g.tickInterval) // Initialize the ticker
-	go func() {
-		for {
-			select {
-			case <-g.ticker.C:
-				g.now.Store(time.Now().Unix())
-			case <-g.done:
-				g.ticker.Stop()
-				return
+	g.now.Store(monotonicNow())
+	done, stopped := make(chan struct{}), make(chan struct{})
+	g.done, g.stopped = done, stopped
+	go g.tick(done, stopped)
+}
+
+// tick refreshes the clock and runs the janitor until done is closed, then closes stopped.
+func (g *cacheGroup) tick(done, stopped chan struct{}) {
+	defer close(stopped)
+	// the clock may have been refreshed after stopTicker cleared it
+	defer g.now.Store(0)
+	timer := time.NewTimer(time.Duration(g.tickInterval.Load()))
+	defer timer.Stop()
+	lastSweep, idle := monotonicNow(), 0
+	for {
+		select {
+		case <-timer.C:
+		case <-g.wake:
+			if !timer.Stop() {
+				<-timer.C
 			}
+			idle = 0
+		case <-done:
+			return
 		}
-	}()
+		now := monotonicNow()
+		g.now.Store(now)
+		// the reads of refresh checks do not count as activity
+		read := g.read.Load()
+		g.refreshAhead(now)
+		if !read {
+			g.read.Store(false)
+		}
+		if time.Duration(now-lastSweep) >= sweepInterval {
+			lastSweep = now
+			g.sweep()
+			// the reads of the janitor do not count as activity
+			g.read.Store(false)
+		}
+
+		if g.read.Load() {
+			g.read.Store(false)
+			idle = 0
+		} else if idle++; idle >= idleTicks {
+			g.now.Store(0)
+			if g.read.Load() {
+				// read while pausing, the reader may have seen the time before the pause and not woken the ticker
+				g.now.Store(now)
+				idle = 0
+			} else {
+				timer.Reset(sweepInterval - time.Duration(now-lastSweep))
+				continue
+			}
+		}
+		timer.Reset(time.Duration(g.tickInterval.Load()))
+	}
+}
+
+// stopTicker stops the ticker and returns a channel closed once its goroutine returned. The group lock must be held.
+func (g *cacheGroup) stopTicker() <-chan struct{} {
+	close(g.done)
+	g.done = nil
+	g.now.Store(0)
+	return g.stopped
+}
+
+// shutdown stops the ticker, if running, and waits for its goroutine to return.
+func (g *cacheGroup) shutdown() {
+	g.mu.Lock()
+	if g.done == nil {
+		g.mu.Unlock()
+		return
+	}
+	stopped := g.stopTicker()
+	g.mu.Unlock()
+	<-stopped
+}
+
+// Shutdown stops the goroutine shared by all caches to keep their clock and remove their expired entries,
+// and waits for it to return. Until a new cache is created, which starts it again, existing caches keep working
+// but read the system time on every access, and expired entries are only removed when replaced or deleted.
+// Closing every cache stops the goroutine as well; Shutdown is for programs or tests that must stop it regardless.
+// It must not be called from a listener.
+func Shutdown() {
+	cacheGroupInstance.shutdown()
 }
 
 // var cacheGroupInstance is a singleton instance of cacheGroup.
 var cacheGroupInstance = newCacheGroup()
 
-// entry represents a cache entry with a value and a timestamp.
-type entry[V any] struct {
-	value     V
-	timeStamp int64
+// entry represents a cache entry with a value, the error returned while computing it and its expiry timestamp.
+// An expireAt of zero means the entry never expires.
+// Entries are immutable once stored except for the list links, which are only touched with the shard lock held,
+// the access flag of their refresh state and the deadline of their sliding state.
+type entry[K comparable, V any] struct {
+	key      K
+	value    V
+	err      error
+	expireAt int64
+	refresh  *refreshState[V] // nil unless the entry is refreshed ahead of expiry
+	sliding  *slidingState    // nil unless the entry expires after a time without access
+
+	prev, next *entry[K, V]
+	list       *entryList[K, V]
+}
+
+// readBufferSize is the number of hits a bounded shard buffers before applying them to its eviction policy.
+const readBufferSize = 64
+
+// shard is a portion of the cache entries guarded by its own lock.
+type shard[K comparable, V any] struct {
+	mu      sync.RWMutex
+	entries map[K]*entry[K, V]
+	calls   map[K]*call[V]
+
+	// policy bounds the number of entries when the cache has a capacity, and is nil otherwise.
+	// Hits are recorded in readBuf without the write lock and replayed on the policy when the buffer fills or on the next write.
+	policy  evictionPolicy[K, V]
+	readBuf []atomic.Pointer[entry[K, V]]
+	reads   atomic.Int32
+
+	// refreshQueue orders the entries refreshed ahead of expiry by the time they are due, see WithRefreshAhead.
+	refreshQueue refreshQueue[K, V]
+
+	stats shardStats
+
+	// listeners are shared by the shards of the cache. Events are queued in pending with the lock held
+	// and delivered by dispatch once it is released, see AddListener.
+	listeners   *listeners[K, V]
+	pending     []Event[K, V]
+	dispatching bool
+
+	_ [64]byte // padding to keep shard locks on separate cache lines
 }
 
 // Cache is a generic cache with a time-to-live (TTL) for each entry.
+// It wraps the cache state so that background work, such as the janitor, references the state without keeping
+// the Cache reachable: once the Cache is garbage collected, a finalizer stops the background work.
 type Cache[K comparable, V any] struct {
-	entries    map[K]entry[V]
-	ttl        int64
-	cacheGroup *cacheGroup
-	mu         sync.RWMutex
-	zeroVal    V
+	*cache[K, V]
+}
+
+// cache is the state of a Cache.
+type cache[K comparable, V any] struct {
+	shards      []shard[K, V]
+	shardMask   uint64
+	keyHash     func(K) uint64
+	hasher      Hasher
+	ttl         int64 // in nanoseconds, 0 means entries never expire
+	errorTTL    int64 // in nanoseconds, 0 means errors are not cached
+	errorFilter func(error) bool
+	staleTTL    int64 // in nanoseconds, 0 means expired entries are never served
+	// sliding makes entries expire after the TTL without access, and at the latest after maxLifetime, 0 meaning never.
+	sliding     bool
+	maxLifetime int64
+	// refreshAhead is the fraction of their TTL after which read entries are refreshed in the background, 0 means never.
+	// refreshSlots bounds the number of refreshes running at a time.
+	refreshAhead float64
+	refreshSlots chan struct{}
+	refreshes    sync.WaitGroup
+	cacheGroup   *cacheGroup
+	clock        Clock
+	// lifecycle guards the registration of the cache with its group once created, see expireWithin and close.
+	// tickTTL is the TTL the clock of the group was acquired for, and expiring whether sweeper is registered.
+	lifecycle sync.Mutex
+	sweeper   *sweeper
+	tickTTL   atomic.Int64
+	expiring  atomic.Bool
+	closed    atomic.Bool
+	listeners listeners[K, V]
+	zeroVal   V
+}
+
+// ErrGoexit is the error returned to the callers waiting on a computation that called runtime.Goexit,
+// as t.FailNow does in tests, instead of returning.
+var ErrGoexit = errors.New("go_memoize: compute function called runtime.Goexit")
+
+// call represents an in-flight computation for a key that other callers can wait on.
+type call[V any] struct {
+	done      chan struct{} // closed once the computation completed
+	value     V
+	ttl       int64 // of the value in nanoseconds, see computation.run
+	err       error
+	panicked  bool
+	recovered any
+}
+
+// newCall returns a call for a computation about to start.
+func newCall[V any]() *call[V] {
+	return &call[V]{done: make(chan struct{})}
+}
+
+// computation is a compute function of a cache, either returning its results with the TTL of the cache in fn,
+// or along with their own TTL in ttlFn, see GetOrComputeWithTTL.
+// ctx, if not nil, is the context of the caller providing the computation: while it waits for the computation
+// of another caller, it stops waiting once ctx is done, and computes anew if that computation failed with a context error.
+type computation[V any] struct {
+	fn    func() (V, error)
+	ttlFn func() (V, time.Duration, error)
+	ctx   context.Context
 }
 
-// NewCache creates a new cache with the specified TTL.
-func NewCache[K comparable, V any](ttl int64) *Cache[K, V] {
-	return &Cache[K, V]{
-		entries:    make(map[K]entry[V]),
-		cacheGroup: cacheGroupInstance,
-		ttl:        ttl,
-		zeroVal:    zeroValue[V](),
+// run calls the compute function and returns its result, along with its TTL, or ttl if it has none.
+func (f computation[V]) run(ttl int64) (V, int64, error) {
+	if f.ttlFn != nil {
+		value, ttl, err := f.ttlFn()
+		return value, int64(ttl), err
 	}
+	value, err := f.fn()
+	return value, ttl, err
 }
 
-// NewCacheSized creates a new cache with the specified size and TTL.
-func NewCacheSized[K comparable, V any](size int, ttl int64) *Cache[K, V] {
-	return &Cache[K, V]{
-		entries:    make(map[K]entry[V], size),
-		cacheGroup: cacheGroupInstance,
-		ttl:        ttl,
-		zeroVal:    zeroValue[V](),
+// wait blocks until the in-flight call completes, or ctx is done, in which case it returns the error of ctx.
+// It re-panics in the waiting goroutine if the computation panicked.
+func (cl *call[V]) wait(ctx context.Context) error {
+	var canceled <-chan struct{}
+	if ctx != nil {
+		canceled = ctx.Done()
+	}
+	select {
+	case <-cl.done:
+	case <-canceled:
+		return ctx.Err()
+	}
+	if cl.panicked {
+		panic(cl.recovered)
+	}
+	return nil
+}
+
+// NewCache creates a new cache with the specified TTL in seconds.
+func NewCache[K comparable, V any](ttl int64, opts ...Option) *Cache[K, V] {
+	return newCache[K, V](0, time.Duration(ttl)*time.Second, nil, opts...)
+}
+
+// NewCacheSized creates a new cache with the specified size and TTL in seconds.
+func NewCacheSized[K comparable, V any](size int, ttl int64, opts ...Option) *Cache[K, V] {
+	return newCache[K, V](size, time.Duration(ttl)*time.Second, nil, opts...)
+}
+
+// NewCacheWithTTL creates a new cache with the specified TTL, which may be shorter than a second.
+func NewCacheWithTTL[K comparable, V any](ttl time.Duration, opts ...Option) *Cache[K, V] {
+	return newCache[K, V](0, ttl, nil, opts...)
+}
+
+// NewCacheSizedWithTTL creates a new cache with the specified size and TTL, which may be shorter than a second.
+func NewCacheSizedWithTTL[K comparable, V any](size int, ttl time.Duration, opts ...Option) *Cache[K, V] {
+	return newCache[K, V](size, ttl, nil, opts...)
+}
+
+// newCache creates a new cache with the specified size, TTL and key hash function.
+// A nil key hash function hashes keys with the configured hasher.
+// The TTL keeps its full precision, bounded in practice by the cache group tick interval.
+func newCache[K comparable, V any](size int, ttl time.Duration, keyHash func(K) uint64, opts ...Option) *Cache[K, V] {
+	o := newOptions(opts)
+	if keyHash == nil {
+		keyHash = defaultKeyHash[K](o.hasher)
+	}
+	c := &cache[K, V]{
+		shards:      make([]shard[K, V], o.shards),
+		shardMask:   uint64(o.shards - 1),
+		keyHash:     keyHash,
+		hasher:      o.hasher,
+		cacheGroup:  cacheGroupInstance,
+		clock:       o.clock,
+		ttl:         int64(ttl),
+		errorTTL:    int64(o.errorTTL),
+		errorFilter: o.errorFilter,
+		staleTTL:    int64(o.staleTTL),
+		sliding:     o.sliding,
+		maxLifetime: int64(o.maxLifetime),
+		zeroVal:     zeroValue[V](),
+	}
+	if o.refreshAhead > 0 && o.refreshAhead < 1 {
+		c.refreshAhead = o.refreshAhead
+		c.refreshSlots = make(chan struct{}, o.refreshWorkers)
+	}
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.entries = make(map[K]*entry[K, V], size/o.shards)
+		s.calls = make(map[K]*call[V])
+		s.listeners = &c.listeners
+		if o.capacity > 0 {
+			capacity := o.capacity / o.shards
+			if i < o.capacity%o.shards {
+				capacity++
+			}
+			s.policy = newEvictionPolicy[K, V](o.evictionPolicy, capacity, keyHash)
+			s.readBuf = make([]atomic.Pointer[entry[K, V]], readBufferSize)
+		}
+	}
+	return startJanitor(c)
+}
+
+// shardFor returns the shard holding the given key.
+func (c *cache[K, V]) shardFor(key K) *shard[K, V] {
+	if c.shardMask == 0 {
+		return &c.shards[0]
+	}
+	return &c.shards[c.keyHash(key)&c.shardMask]
+}
+
+// keyHasher returns the hasher of the cache when it uses key hashes, to select a shard or for its eviction policy,
+// and nil otherwise.
+func (c *cache[K, V]) keyHasher() Hasher {
+	if c.shardMask != 0 || c.shards[0].policy != nil {
+		return c.hasher
+	}
+	return nil
+}
+
+// defaultKeyHash returns a key hash function hashing keys with the given hasher.
+func defaultKeyHash[K comparable](hasher Hasher) func(K) uint64 {
+	return func(key K) uint64 {
+		return hash1(hasher, key)
 	}
 }
 
 // NowUnix returns the current Unix timestamp from the cache group.
 func (c *Cache[K, V]) NowUnix() int64 {
-	return c.cacheGroup.now.Load().(int64)
+	return c.now() / int64(time.Second)
+}
+
+// now returns the current time in nanoseconds from the clock of the cache, or from the cache group by default.
+func (c *cache[K, V]) now() int64 {
+	if c.clock != nil {
+		return c.clock.Now()
+	}
+	return c.cacheGroup.Now()
+}
+
+// lookup returns the live entry for the given key, recording the hit, or nil if the key is missing or expired.
+// Memoized functions call it before GetOrCompute so that the closure binding their arguments is only allocated on a miss:
+// the compute function escapes to the heap, as it may be kept by a background refresh.
+func (c *cache[K, V]) lookup(key K) *entry[K, V] {
+	s := c.shardFor(key)
+	s.mu.RLock()
+	e, ok := s.entries[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	now := c.now()
+	if !e.alive(now) {
+		return nil
+	}
+	s.stats.hits.Add(1)
+	s.recordAccess(e, now)
+	return e
 }
 
 // GetOrCompute retrieves the value for the given key or computes it using the provided function if not present or expired.
+// Concurrent misses on the same key share a single call of computeFn, see GetOrComputeE.
 func (c *Cache[K, V]) GetOrCompute(key K, computeFn func() V) V {
-	c.mu.RLock()
-	existingEntry, ok := c.entries[key]
-	c.mu.RUnlock()
+	value, _ := c.GetOrComputeE(key, func() (V, error) {
+		return computeFn(), nil
+	})
+	return value
+}
+
+// GetOrComputeE retrieves the value for the given key or computes it using the provided function if not present or expired.
+// Only one computation runs per key at a time; concurrent callers for the same key wait for it and receive its value and error.
+// A result is stored when computeFn returns a nil error, or when the error is cacheable as configured by WithErrorTTL and WithErrorFilter.
+// A key that is not equal to itself, such as a NaN float or a struct holding one, is computed on every call and never stored.
+func (c *Cache[K, V]) GetOrComputeE(key K, computeFn func() (V, error)) (V, error) {
+	return c.getOrCompute(key, computeFn, computeFn)
+}
+
+// GetOrComputeWithTTL is like GetOrComputeE for a compute function returning the TTL of its result along with it,
+// which the result expires after instead of the TTL of the cache. A TTL of zero means the result never expires,
+// and a result with a negative TTL is returned without being stored. Cached errors expire after the error TTL.
+func (c *Cache[K, V]) GetOrComputeWithTTL(key K, computeFn func() (V, time.Duration, error)) (V, error) {
+	fn := computation[V]{ttlFn: computeFn}
+	return c.getOrComputeWith(key, fn, fn)
+}
+
+// getOrCompute implements GetOrComputeE, computing in the background with refreshFn instead of computeFn,
+// see WithStaleWhileRevalidate and WithRefreshAhead. Memoized functions taking a context use it to refresh
+// with a context that is not canceled along with the context of their caller.
+func (c *cache[K, V]) getOrCompute(key K, computeFn, refreshFn func() (V, error)) (V, error) {
+	return c.getOrComputeWith(key, computation[V]{fn: computeFn}, computation[V]{fn: refreshFn})
+}
+
+// getOrComputeCtx implements getOrCompute for a caller with a context, see computation.
+func (c *cache[K, V]) getOrComputeCtx(ctx context.Context, key K, computeFn, refreshFn func() (V, error)) (V, error) {
+	return c.getOrComputeWith(key, computation[V]{fn: computeFn, ctx: ctx}, computation[V]{fn: refreshFn})
+}
+
+// getOrComputeWith implements getOrCompute and GetOrComputeWithTTL.
+func (c *cache[K, V]) getOrComputeWith(key K, computeFn, refreshFn computation[V]) (V, error) {
+	s := c.shardFor(key)
+	s.mu.RLock()
+	existingEntry, ok := s.entries[key]
+	s.mu.RUnlock()
+
+	if ok {
+		now := c.now()
+		if existingEntry.alive(now) {
+			s.stats.hits.Add(1)
+			s.recordAccess(existingEntry, now)
+			return existingEntry.value, existingEntry.err
+		}
+		if c.servableStale(existingEntry, now) {
+			s.stats.staleHits.Add(1)
+			s.recordAccess(existingEntry, now)
+			c.computeInBackground(s, key, refreshFn, nil)
+			return existingEntry.value, existingEntry.err
+		}
+	}
+
+	if key != key {
+		// A key that is not equal to itself, such as a NaN float, is never found in the maps of the shard:
+		// compute it without registering the call or storing the result, which could be neither read nor removed.
+		s.stats.misses.Add(1)
+		cl := newCall[V]()
+		c.doCall(s, key, cl, computeFn, refreshFn, false)
+		return cl.value, cl.err
+	}
+
+	s.mu.Lock()
+	now := c.now()
+	if existingEntry, ok = s.entries[key]; ok && existingEntry.alive(now) {
+		s.mu.Unlock()
+		s.stats.hits.Add(1)
+		s.recordAccess(existingEntry, now)
+		return existingEntry.value, existingEntry.err
+	}
+	s.stats.misses.Add(1)
+	if inFlight, ok := s.calls[key]; ok {
+		s.mu.Unlock()
+		if err := inFlight.wait(computeFn.ctx); err != nil {
+			return c.zeroVal, err
+		}
+		if computeFn.ctx != nil && computeFn.ctx.Err() == nil && isContextError(inFlight.err) {
+			// the context of the caller that computed is done, not the one of this caller
+			return c.getOrComputeWith(key, computeFn, refreshFn)
+		}
+		return inFlight.value, inFlight.err
+	}
+	cl := newCall[V]()
+	s.calls[key] = cl
+	s.mu.Unlock()
+
+	c.doCall(s, key, cl, computeFn, refreshFn, false)
+	return cl.value, cl.err
+}
+
+// computeInBackground recomputes the entry for key in a new goroutine, unless a computation is already in flight for it,
+// and reports whether it started. done, if not nil, is called once the computation completes.
+// A computation that fails or panics leaves the current entry in place; callers waiting on it still see the error or panic.
+func (c *cache[K, V]) computeInBackground(s *shard[K, V], key K, computeFn computation[V], done func()) bool {
+	s.mu.RLock()
+	_, inFlight := s.calls[key]
+	s.mu.RUnlock()
+	if inFlight {
+		return false
+	}
+
+	s.mu.Lock()
+	if _, inFlight = s.calls[key]; inFlight {
+		s.mu.Unlock()
+		return false
+	}
+	cl := newCall[V]()
+	s.calls[key] = cl
+	s.mu.Unlock()
+
+	go func() {
+		defer func() {
+			_ = recover()
+			if done != nil {
+				done()
+			}
+		}()
+		c.doCall(s, key, cl, computeFn, computeFn, true)
+	}()
+	return true
+}
+
+// doCall runs computeFn for the in-flight call without holding the shard lock, then stores the result,
+// along with refreshFn to refresh it ahead of expiry. The result is discarded if the key was set or deleted while computing,
+// so the newer write wins. A background call only stores successful results, so as not to replace an entry with an error.
+// If computeFn panics, waiting callers are released and the panic is propagated to them. If it calls runtime.Goexit,
+// waiting callers are released with ErrGoexit and the calling goroutine exits.
+func (c *cache[K, V]) doCall(s *shard[K, V], key K, cl *call[V], computeFn, refreshFn computation[V], background bool) {
+	normalReturn, recovered := false, false
+	start := time.Now()
+	defer func() {
+		if !normalReturn && !recovered {
+			// computeFn called runtime.Goexit, which keeps unwinding the goroutine after this function
+			cl.err = ErrGoexit
+		}
+		s.stats.recordCompute(time.Since(start), cl.panicked || cl.err != nil)
+		s.mu.Lock()
+		// compared by identity, which also skips the calls never registered for keys not equal to themselves
+		if s.calls[key] == cl {
+			delete(s.calls, key)
+			if normalReturn && (cl.err == nil || !background) {
+				c.store(s, key, cl.value, cl.err, cl.ttl, refreshFn)
+			}
+		}
+		s.mu.Unlock()
+		close(cl.done)
+		s.dispatch()
+		if cl.panicked {
+			panic(cl.recovered)
+		}
+	}()
+
+	func() {
+		defer func() {
+			// recover returns nil while unwinding from runtime.Goexit
+			if !normalReturn {
+				if r := recover(); r != nil {
+					cl.panicked, cl.recovered = true, r
+				}
+			}
+		}()
+		cl.value, cl.ttl, cl.err = computeFn.run(c.ttl)
+		normalReturn = true
+	}()
+	if !normalReturn {
+		recovered = true
+		return
+	}
+	if cl.err == nil && cl.ttl > 0 && cl.ttl != c.ttl {
+		c.expireWithin(cl.ttl)
+	}
+}
+
+// store saves a computed result in the locked shard, applying the TTL of a successful result, or the error TTL.
+// A successful result with a negative TTL is not stored. Successful results are refreshed ahead of expiry with refreshFn
+// when the cache is configured to, see WithRefreshAhead.
+func (c *cache[K, V]) store(s *shard[K, V], key K, value V, err error, ttl int64, refreshFn computation[V]) {
+	now := c.now()
+	if err == nil {
+		if ttl < 0 {
+			return
+		}
+		e := c.newEntry(key, value, ttl, now)
+		if c.refreshAhead > 0 && ttl > 0 {
+			e.refresh = &refreshState[V]{at: now + int64(float64(ttl)*c.refreshAhead), compute: refreshFn}
+			s.refreshQueue.push(e.refresh.at, e)
+		}
+		s.put(e)
+	} else if c.cacheableError(err) {
+		s.put(&entry[K, V]{key: key, value: value, err: err, expireAt: expiry(now, c.errorTTL)})
+	}
+}
+
+// cacheableError reports whether the given compute error should be stored in the cache.
+// Cancellation errors are never stored: they come from the context of one caller and must not be served to others.
+func (c *cache[K, V]) cacheableError(err error) bool {
+	if isContextError(err) {
+		return false
+	}
+	return c.errorTTL > 0 && (c.errorFilter == nil || c.errorFilter(err))
+}
+
+// isContextError reports whether err reports the cancellation or the deadline of a context.
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// expiry returns the expiry timestamp for an entry stored at now with the given TTL, or zero if it never expires.
+func expiry(now, ttl int64) int64 {
+	if ttl == 0 {
+		return 0
+	}
+	return now + ttl
+}
+
+// servableStale reports whether the expired entry can still be served while it is refreshed, see WithStaleWhileRevalidate.
+// Errors are never served stale.
+func (c *cache[K, V]) servableStale(e *entry[K, V], now int64) bool {
+	expireAt := e.expiry()
+	return c.staleTTL > 0 && e.err == nil && expireAt != 0 && now < expireAt+c.staleTTL
+}
+
+// alive reports whether the entry is still valid at the given time.
+func (e *entry[K, V]) alive(now int64) bool {
+	if e.sliding != nil && now >= e.sliding.deadline.Load() {
+		return false
+	}
+	return e.expireAt == 0 || now < e.expireAt
+}
+
+// expiry returns the time at which the entry expires unless accessed, or zero if it never expires.
+func (e *entry[K, V]) expiry() int64 {
+	if e.sliding != nil {
+		if deadline := e.sliding.deadline.Load(); e.expireAt == 0 || deadline < e.expireAt {
+			return deadline
+		}
+	}
+	return e.expireAt
+}
+
+// put inserts or replaces an entry in the locked shard, evicting an entry chosen by the eviction policy when over capacity.
+func (s *shard[K, V]) put(e *entry[K, V]) {
+	old, ok := s.entries[e.key]
+	s.entries[e.key] = e
+	if ok {
+		s.emit(EventUpdate, e, old)
+	} else {
+		s.emit(EventInsert, e, nil)
+	}
+	if s.policy == nil {
+		return
+	}
+	s.drainReads()
+	if ok {
+		s.policy.replace(old, e)
+		return
+	}
+	if victim := s.policy.add(e); victim != nil {
+		s.policy.remove(victim)
+		delete(s.entries, victim.key)
+		s.stats.evictions.Add(1)
+		s.emit(EventEvict, victim, nil)
+	}
+}
 
-	now := c.NowUnix()
-	if ok && (c.ttl == 0 || now-existingEntry.timeStamp < c.ttl) {
-		return existingEntry.value
+// remove deletes the entry for the given key from the locked shard, reporting the removal with the given reason.
+func (s *shard[K, V]) remove(key K, reason EventReason) {
+	e, ok := s.entries[key]
+	if !ok {
+		return
 	}
+	delete(s.entries, key)
+	if s.policy != nil {
+		s.policy.remove(e)
+	}
+	s.emit(reason, e, nil)
+}
 
-	c.mu.Lock()
-	newVal := computeFn()
-	c.entries[key] = entry[V]{value: newVal, timeStamp: now}
-	c.mu.Unlock()
-	return newVal
+// recordAccess records a hit on e at now for refresh-ahead, sliding expiration and the eviction policy. It never blocks:
+// when the read buffer is full and the shard lock is busy, the hit is dropped, which only makes the policy approximate.
+func (s *shard[K, V]) recordAccess(e *entry[K, V], now int64) {
+	e.touch()
+	e.slide(now)
+	if s.policy == nil {
+		return
+	}
+	if n := s.reads.Load(); n < readBufferSize {
+		if i := s.reads.Add(1) - 1; i < readBufferSize {
+			s.readBuf[i].Store(e)
+			if i < readBufferSize-1 {
+				return
+			}
+		}
+	}
+	if s.mu.TryLock() {
+		s.drainReads()
+		s.mu.Unlock()
+	}
+}
+
+// drainReads replays the buffered hits on the eviction policy. The shard lock must be held.
+func (s *shard[K, V]) drainReads() {
+	n := int(s.reads.Load())
+	if n > readBufferSize {
+		n = readBufferSize
+	}
+	for i := 0; i < n; i++ {
+		if e := s.readBuf[i].Swap(nil); e != nil && e.list != nil {
+			s.policy.access(e)
+		}
+	}
+	s.reads.Store(0)
 }
 
 // Delete removes the entry for the given key from the cache.
+// A computation in flight for the key completes for its callers but its result is not stored.
 func (c *Cache[K, V]) Delete(key K) {
-	c.mu.Lock()
-	delete(c.entries, key)
-	c.mu.Unlock()
+	s := c.shardFor(key)
+	s.mu.Lock()
+	s.remove(key, EventDelete)
+	delete(s.calls, key)
+	s.mu.Unlock()
+	s.dispatch()
 }
 
 // Set adds or updates the value for the given key in the cache.
+// It takes precedence over a computation in flight for the key. A key that is not equal to itself is not stored.
 func (c *Cache[K, V]) Set(key K, value V) {
-	timeStamp := c.NowUnix()
-	c.mu.Lock()
-	c.entries[key] = entry[V]{value: value, timeStamp: timeStamp}
-	c.mu.Unlock()
+	c.set(key, value, c.ttl)
+}
+
+// SetWithTTL adds or updates the value for the given key in the cache like Set, expiring it after ttl
+// instead of the TTL of the cache. A ttl of zero means the entry never expires, and a negative ttl deletes it.
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	if ttl < 0 {
+		c.Delete(key)
+		return
+	}
+	if ttl > 0 && int64(ttl) != c.ttl {
+		c.expireWithin(int64(ttl))
+	}
+	c.set(key, value, int64(ttl))
+}
+
+// set implements Set and SetWithTTL.
+func (c *cache[K, V]) set(key K, value V, ttl int64) {
+	if key != key {
+		// never found again, see getOrComputeWith
+		return
+	}
+	e := c.newEntry(key, value, ttl, c.now())
+	s := c.shardFor(key)
+	s.mu.Lock()
+	s.put(e)
+	delete(s.calls, key)
+	s.mu.Unlock()
+	s.dispatch()
 }
 
 // Get retrieves the value for the given key from the cache if present and not expired.
+// Cached errors are reported as missing.
 func (c *Cache[K, V]) Get(key K) (V, bool) {
-	c.mu.RLock()
-	entry, ok := c.entries[key]
-	c.mu.RUnlock()
+	s := c.shardFor(key)
+	s.mu.RLock()
+	entry, ok := s.entries[key]
+	s.mu.RUnlock()
 
-	if ok && (c.ttl == 0 || c.NowUnix()-entry.timeStamp < c.ttl) {
+	if ok && entry.err == nil {
+		if now := c.now(); entry.alive(now) {
+			s.stats.hits.Add(1)
+			s.recordAccess(entry, now)
+			return entry.value, true
+		}
+	}
+
+	s.stats.misses.Add(1)
+	return c.zeroVal, false
+}
+
+// peek retrieves the value for the given key like Get, without recording the hit.
+func (c *cache[K, V]) peek(key K) (V, bool) {
+	s := c.shardFor(key)
+	s.mu.RLock()
+	entry, ok := s.entries[key]
+	s.mu.RUnlock()
+
+	if ok && entry.err == nil && entry.alive(c.now()) {
 		return entry.value, true
 	}
 
 	return c.zeroVal, false
 }
+
+// Len returns the number of entries in the cache, including expired entries the janitor has not removed yet.
+func (c *Cache[K, V]) Len() int {
+	n := 0
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.RLock()
+		n += len(s.entries)
+		s.mu.RUnlock()
+	}
+	return n
+}
+
+// Purge removes all entries from the cache.
+// Computations in flight complete for their callers but their results are not stored.
+func (c *Cache[K, V]) Purge() {
+	for i := range c.shards {
+		s := &c.shards[i]
+		s.mu.Lock()
+		for key := range s.entries {
+			s.remove(key, EventDelete)
+		}
+		clear(s.calls)
+		s.refreshQueue.reset()
+		s.mu.Unlock()
+		s.dispatch()
+	}
+}
@@ -0,0 +1,607 @@
+package go_memoize
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheGetIgnoresCachedErrors(t *testing.T) {
+	cache := NewCache[int, int](0, WithErrorTTL(1*time.Minute))
+	cache.GetOrComputeE(1, func() (int, error) { return 0, errCompute })
+	if _, ok := cache.Get(1); ok {
+		t.Errorf("Expected cached error to be reported as missing")
+	}
+	if _, err := cache.GetOrComputeE(1, func() (int, error) { return 1, nil }); !errors.Is(err, errCompute) {
+		t.Errorf("Expected %v, got %v", errCompute, err)
+	}
+}
+
+func TestCacheWithSubSecondTTL(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCacheWithTTL[int, int](100*time.Millisecond, WithClock(clock))
+	cache.Set(1, 42)
+	clock.Advance(50 * time.Millisecond)
+	if v, ok := cache.Get(1); !ok || v != 42 {
+		t.Errorf("Expected 42, got %d", v)
+	}
+
+	clock.Advance(100 * time.Millisecond)
+	if _, ok := cache.Get(1); ok {
+		t.Errorf("Expected entry to expire after 100ms")
+	}
+}
+
+func TestCacheGetOrCompute_SingleComputePerKey(t *testing.T) {
+	cache := NewCache[int, int](0)
+	var count int32
+	release := make(chan struct{})
+	computeFn := func() int {
+		atomic.AddInt32(&count, 1)
+		<-release
+		return 42
+	}
+
+	const callers = 10
+	results := make(chan int, callers)
+	var wg sync.WaitGroup
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer wg.Done()
+			results <- cache.GetOrCompute(1, computeFn)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	for result := range results {
+		if result != 42 {
+			t.Errorf("Expected 42, got %d", result)
+		}
+	}
+	if atomic.LoadInt32(&count) != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+}
+
+func TestCacheGetOrCompute_OtherKeysUnaffected(t *testing.T) {
+	cache := NewCache[int, int](0)
+	cache.Set(2, 2)
+	release := make(chan struct{})
+	defer close(release)
+	go cache.GetOrCompute(1, func() int {
+		<-release
+		return 1
+	})
+	time.Sleep(10 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		cache.GetOrCompute(3, func() int { return 3 })
+		if v, ok := cache.Get(2); !ok || v != 2 {
+			t.Errorf("Expected 2, got %d", v)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected other keys not to wait for an in-flight computation")
+	}
+}
+
+func TestCacheGetOrCompute_PanicReleasesWaiters(t *testing.T) {
+	cache := NewCache[int, int](0)
+	release := make(chan struct{})
+	computing := make(chan struct{})
+	go func() {
+		defer func() { recover() }()
+		cache.GetOrCompute(1, func() int {
+			close(computing)
+			<-release
+			panic("boom")
+		})
+	}()
+	<-computing
+
+	waiterPanicked := make(chan any, 1)
+	go func() {
+		defer func() { waiterPanicked <- recover() }()
+		cache.GetOrCompute(1, func() int { return 1 })
+	}()
+	// the waiter has joined the computation once it counted its miss
+	waitFor(t, func() bool { return cache.Stats().Misses == 2 })
+	close(release)
+
+	select {
+	case r := <-waiterPanicked:
+		if r != "boom" {
+			t.Errorf("Expected boom, got %v", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected waiter to be released after a panicking computation")
+	}
+	if v := cache.GetOrCompute(1, func() int { return 2 }); v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+}
+
+func TestCacheGetOrCompute_GoexitReleasesWaiters(t *testing.T) {
+	cache := NewCache[int, int](0)
+	release := make(chan struct{})
+	computing := make(chan struct{})
+	exited := make(chan bool)
+	go func() {
+		returned := false
+		defer func() { exited <- returned }()
+		cache.GetOrComputeE(1, func() (int, error) {
+			close(computing)
+			<-release
+			runtime.Goexit()
+			return 1, nil
+		})
+		returned = true
+	}()
+	<-computing
+
+	waiter := make(chan error, 1)
+	go func() {
+		_, err := cache.GetOrComputeE(1, func() (int, error) { return 2, nil })
+		waiter <- err
+	}()
+	waitFor(t, func() bool { return cache.Stats().Misses == 2 })
+	close(release)
+
+	if returned := <-exited; returned {
+		t.Errorf("Expected the computing goroutine to exit")
+	}
+	select {
+	case err := <-waiter:
+		if !errors.Is(err, ErrGoexit) {
+			t.Errorf("Expected %v, got %v", ErrGoexit, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected waiter to be released after a computation calling runtime.Goexit")
+	}
+	if v, err := cache.GetOrComputeE(1, func() (int, error) { return 3, nil }); v != 3 || err != nil {
+		t.Errorf("Expected 3, got %d and %v", v, err)
+	}
+}
+
+func TestCacheGetOrComputeCtx_WaiterRecomputesAfterCancellationOfCaller(t *testing.T) {
+	cache := NewCache[int, int](60)
+	ctx, cancel := context.WithCancel(context.Background())
+	computing := make(chan struct{})
+	first := make(chan error, 1)
+	go func() {
+		_, err := cache.getOrComputeCtx(ctx, 1, func() (int, error) {
+			close(computing)
+			<-ctx.Done()
+			return 0, fmt.Errorf("loading 1: %w", ctx.Err())
+		}, nil)
+		first <- err
+	}()
+	<-computing
+
+	waiter := make(chan int, 1)
+	go func() {
+		v, _ := cache.getOrComputeCtx(context.Background(), 1, func() (int, error) { return 2, nil }, nil)
+		waiter <- v
+	}()
+	waitFor(t, func() bool { return cache.Stats().Misses == 2 })
+	cancel()
+
+	if err := <-first; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+	if v := <-waiter; v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+}
+
+func TestCacheGetOrComputeCtx_WaiterStopsOnItsCancellation(t *testing.T) {
+	cache := NewCache[int, int](60)
+	release := make(chan struct{})
+	computing := make(chan struct{})
+	go cache.GetOrCompute(1, func() int {
+		close(computing)
+		<-release
+		return 1
+	})
+	<-computing
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	waiter := make(chan error, 1)
+	go func() {
+		_, err := cache.getOrComputeCtx(ctx, 1, func() (int, error) { return 2, nil }, nil)
+		waiter <- err
+	}()
+	waitFor(t, func() bool { return cache.Stats().Misses == 2 })
+	cancel()
+
+	select {
+	case err := <-waiter:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected %v, got %v", context.Canceled, err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Expected waiter to stop waiting once its context is canceled")
+	}
+}
+
+func TestCacheSetDuringComputeWins(t *testing.T) {
+	cache := NewCache[int, int](0)
+	release := make(chan struct{})
+	computing := make(chan struct{})
+	done := make(chan int)
+	go func() {
+		done <- cache.GetOrCompute(1, func() int {
+			close(computing)
+			<-release
+			return 1
+		})
+	}()
+	<-computing
+	cache.Set(1, 2)
+	close(release)
+
+	if v := <-done; v != 1 {
+		t.Errorf("Expected the caller to receive its computed value 1, got %d", v)
+	}
+	if v, _ := cache.Get(1); v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+}
+
+func TestCacheDeleteDuringComputeDiscardsResult(t *testing.T) {
+	cache := NewCache[int, int](0)
+	release := make(chan struct{})
+	computing := make(chan struct{})
+	done := make(chan int)
+	go func() {
+		done <- cache.GetOrCompute(1, func() int {
+			close(computing)
+			<-release
+			return 1
+		})
+	}()
+	<-computing
+	cache.Delete(1)
+	close(release)
+	<-done
+
+	if _, ok := cache.Get(1); ok {
+		t.Errorf("Expected the result computed before Delete not to be stored")
+	}
+}
+
+func TestCacheWithShards(t *testing.T) {
+	cache := NewCache[int, int](0, WithShards(5))
+	if len(cache.shards) != 8 {
+		t.Errorf("Expected 8, got %d", len(cache.shards))
+	}
+	for i := 0; i < 1000; i++ {
+		cache.Set(i, i*2)
+	}
+	for i := 0; i < 1000; i++ {
+		if v, ok := cache.Get(i); !ok || v != i*2 {
+			t.Errorf("Expected %d, got %d", i*2, v)
+		}
+	}
+	used := 0
+	for i := range cache.shards {
+		if len(cache.shards[i].entries) > 0 {
+			used++
+		}
+	}
+	if used != len(cache.shards) {
+		t.Errorf("Expected entries in all %d shards, got %d", len(cache.shards), used)
+	}
+}
+
+func TestCacheWithCapacity_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewCache[int, int](0, WithCapacity(3))
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	cache.Set(3, 3)
+	cache.Get(1)
+	cache.Set(4, 4)
+
+	if _, ok := cache.Get(2); ok {
+		t.Errorf("Expected 2 to be evicted")
+	}
+	for _, key := range []int{1, 3, 4} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("Expected %d to be cached", key)
+		}
+	}
+
+	cache.Set(3, 30)
+	cache.Set(5, 5)
+	if _, ok := cache.Get(1); ok {
+		t.Errorf("Expected 1 to be evicted")
+	}
+	if v, _ := cache.Get(3); v != 30 {
+		t.Errorf("Expected 30, got %d", v)
+	}
+}
+
+func TestCacheWithCapacity_BoundsShardedCache(t *testing.T) {
+	cache := NewCache[int, int](0, WithCapacity(10), WithShards(64))
+	if n := len(cache.shards); n != 8 {
+		t.Errorf("Expected 8, got %d", n)
+	}
+	for i := 0; i < 1000; i++ {
+		cache.Set(i, i)
+	}
+	if n := cacheLen(cache); n > 10 {
+		t.Errorf("Expected at most 10, got %d", n)
+	}
+}
+
+func TestCacheWithCapacity_Delete(t *testing.T) {
+	cache := NewCache[int, int](0, WithCapacity(2))
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+	cache.Delete(1)
+	cache.Set(3, 3)
+	for _, key := range []int{2, 3} {
+		if _, ok := cache.Get(key); !ok {
+			t.Errorf("Expected %d to be cached", key)
+		}
+	}
+}
+
+func TestCacheWithCapacity_ConcurrentAccess(t *testing.T) {
+	cache := NewCache[int, int](0, WithCapacity(64), WithShards(4))
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 10000; i++ {
+				key := (i * (g + 1)) % 500
+				if v := cache.GetOrCompute(key, func() int { return key * 2 }); v != key*2 {
+					t.Errorf("Expected %d, got %d", key*2, v)
+				}
+				if i%100 == 0 {
+					cache.Delete(key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	for i := range cache.shards {
+		s := &cache.shards[i]
+		if len(s.entries) > 16 || s.policy.len() != len(s.entries) {
+			t.Errorf("Expected at most 16 entries tracked by the policy, got %d entries and %d tracked", len(s.entries), s.policy.len())
+		}
+	}
+}
+
+func TestMemoize1_StaleWhileRevalidate(t *testing.T) {
+	clock := NewFakeClock()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	computeFn := func(k int) int {
+		if n := calls.Add(1); n > 1 {
+			<-release
+		}
+		return int(calls.Load())
+	}
+	memoizedFn := Memoize1(computeFn, time.Second, WithClock(clock), WithStaleWhileRevalidate(time.Second))
+	if v := memoizedFn(1); v != 1 {
+		t.Errorf("Expected 1, got %d", v)
+	}
+
+	// Within the stale window, callers get the stale value at once while a single refresh runs.
+	clock.Advance(1500 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		if v := memoizedFn(1); v != 1 {
+			t.Errorf("Expected 1, got %d", v)
+		}
+	}
+	close(release)
+	waitFor(t, func() bool { return memoizedFn(1) == 2 })
+	if n := calls.Load(); n != 2 {
+		t.Errorf("Expected 2, got %d", n)
+	}
+}
+
+func TestMemoize1_StaleWindowPassedBlocksOnCompute(t *testing.T) {
+	clock := NewFakeClock()
+	count := 0
+	memoizedFn := Memoize1(func(k int) int {
+		count++
+		return count
+	}, time.Second, WithClock(clock), WithStaleWhileRevalidate(time.Second))
+	memoizedFn(1)
+	clock.Advance(2500 * time.Millisecond)
+	if v := memoizedFn(1); v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+}
+
+func TestStaleWhileRevalidate_FailedRefreshKeepsStaleValue(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](1, WithClock(clock), WithStaleWhileRevalidate(time.Minute))
+	cache.Set(1, 1)
+	clock.Advance(2 * time.Second)
+
+	var refreshes atomic.Int32
+	failing := func() (int, error) {
+		refreshes.Add(1)
+		return 0, errCompute
+	}
+	if v, err := cache.GetOrComputeE(1, failing); v != 1 || err != nil {
+		t.Errorf("Expected 1, got %d and %v", v, err)
+	}
+	waitFor(t, func() bool { return refreshes.Load() == 1 && !inFlight(cache, 1) })
+	if v, err := cache.GetOrComputeE(1, failing); v != 1 || err != nil {
+		t.Errorf("Expected 1, got %d and %v", v, err)
+	}
+	panicking := func() (int, error) { panic("refresh failed") }
+	waitFor(t, func() bool { return !inFlight(cache, 1) })
+	if v, _ := cache.GetOrComputeE(1, panicking); v != 1 {
+		t.Errorf("Expected 1, got %d", v)
+	}
+}
+
+func TestStaleWhileRevalidate_ErrorsAreNotServedStale(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](1, WithClock(clock), WithErrorTTL(time.Second), WithStaleWhileRevalidate(time.Minute))
+	cache.GetOrComputeE(1, func() (int, error) { return 0, errCompute })
+	clock.Advance(2 * time.Second)
+	if v, err := cache.GetOrComputeE(1, func() (int, error) { return 5, nil }); v != 5 || err != nil {
+		t.Errorf("Expected 5, got %d and %v", v, err)
+	}
+}
+
+func TestStaleWhileRevalidate_JanitorKeepsStaleEntries(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](1, WithClock(clock), WithStaleWhileRevalidate(time.Minute))
+	cache.Set(1, 1)
+	clock.Advance(30 * time.Second)
+	cache.sweeper.sweep()
+	if n := cacheLen(cache); n != 1 {
+		t.Errorf("Expected 1, got %d", n)
+	}
+	clock.Advance(time.Minute)
+	cache.sweeper.sweep()
+	if n := cacheLen(cache); n != 0 {
+		t.Errorf("Expected 0, got %d", n)
+	}
+}
+
+func TestCacheSetWithTTL(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](60, WithClock(clock))
+	cache.SetWithTTL(1, 1, time.Second)
+	cache.Set(2, 2)
+	cache.SetWithTTL(3, 3, 0)
+
+	clock.Advance(2 * time.Second)
+	if _, ok := cache.Get(1); ok {
+		t.Errorf("Expected 1 to be expired")
+	}
+	if value, ok := cache.Get(2); !ok || value != 2 {
+		t.Errorf("Expected 2, got %d", value)
+	}
+	clock.Advance(time.Hour)
+	if value, ok := cache.Get(3); !ok || value != 3 {
+		t.Errorf("Expected 3, got %d", value)
+	}
+
+	cache.SetWithTTL(3, 4, -time.Second)
+	if _, ok := cache.Get(3); ok {
+		t.Errorf("Expected 3 to be deleted")
+	}
+}
+
+func TestCacheSetWithTTLRegistersJanitor(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](0, WithClock(clock))
+	if cache.sweeper != nil {
+		t.Fatalf("Expected no sweeper for a cache without TTL")
+	}
+	cache.SetWithTTL(1, 1, time.Minute)
+	cache.Set(2, 2)
+	if cache.sweeper == nil || !isRegistered(cache.sweeper) {
+		t.Fatalf("Expected the sweeper to be registered")
+	}
+	clock.Advance(2 * time.Minute)
+	cache.sweeper.sweep()
+	if n := cacheLen(cache); n != 1 {
+		t.Errorf("Expected 1, got %d", n)
+	}
+}
+
+func TestCacheSetWithTTLTunesClock(t *testing.T) {
+	cache := NewCache[int, int](60)
+	defer cache.Close()
+	cache.SetWithTTL(1, 1, 160*time.Millisecond)
+	if interval := time.Duration(cacheGroupInstance.tickInterval.Load()); interval > 10*time.Millisecond {
+		t.Errorf("Expected at most %v, got %v", 10*time.Millisecond, interval)
+	}
+	if ttl := cache.tickTTL.Load(); ttl != int64(160*time.Millisecond) {
+		t.Errorf("Expected %d, got %d", int64(160*time.Millisecond), ttl)
+	}
+	cache.SetWithTTL(2, 2, time.Minute)
+	if ttl := cache.tickTTL.Load(); ttl != int64(160*time.Millisecond) {
+		t.Errorf("Expected %d, got %d", int64(160*time.Millisecond), ttl)
+	}
+}
+
+func TestCacheGetOrComputeWithTTL(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[string, int](0, WithClock(clock))
+	calls := 0
+	compute := func(ttl time.Duration) func() (int, time.Duration, error) {
+		return func() (int, time.Duration, error) {
+			calls++
+			return calls, ttl, nil
+		}
+	}
+
+	cache.GetOrComputeWithTTL("short", compute(time.Second))
+	cache.GetOrComputeWithTTL("long", compute(time.Minute))
+	cache.GetOrComputeWithTTL("none", compute(-1))
+	if value, _ := cache.GetOrComputeWithTTL("none", compute(-1)); value != 4 {
+		t.Errorf("Expected a result with a negative TTL not to be cached, got %d", value)
+	}
+
+	clock.Advance(2 * time.Second)
+	if value, _ := cache.GetOrComputeWithTTL("short", compute(time.Second)); value != 5 {
+		t.Errorf("Expected 5, got %d", value)
+	}
+	if value, _ := cache.GetOrComputeWithTTL("long", compute(time.Second)); value != 2 {
+		t.Errorf("Expected 2, got %d", value)
+	}
+}
+
+func TestCacheGetOrComputeWithTTLAppliesErrorTTL(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](0, WithClock(clock), WithErrorTTL(time.Second))
+	calls := 0
+	computeFn := func() (int, time.Duration, error) {
+		calls++
+		return 0, time.Hour, errCompute
+	}
+	cache.GetOrComputeWithTTL(1, computeFn)
+	cache.GetOrComputeWithTTL(1, computeFn)
+	clock.Advance(2 * time.Second)
+	cache.GetOrComputeWithTTL(1, computeFn)
+	if calls != 2 {
+		t.Errorf("Expected 2, got %d", calls)
+	}
+}
+
+// waitFor polls cond until it holds, failing the test after a second.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); !cond(); {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for condition")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// inFlight reports whether a computation is in flight for the key.
+func inFlight[K comparable, V any](c *Cache[K, V], key K) bool {
+	s := c.shardFor(key)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.calls[key]
+	return ok
+}
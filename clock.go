@@ -0,0 +1,59 @@
+package go_memoize
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Clock provides the current time to a cache.
+// Now returns a timestamp in nanoseconds; only the difference between two timestamps is meaningful.
+type Clock interface {
+	Now() int64
+}
+
+// epoch is the reference of monotonicNow, taken when the package is initialized.
+var (
+	epoch         = time.Now()
+	epochUnixNano = epoch.UnixNano()
+)
+
+// monotonicNow returns the current time in nanoseconds, measured on the monotonic clock from epoch:
+// it follows the Unix time at epoch but is not affected by later changes of the wall clock.
+func monotonicNow() int64 {
+	return epochUnixNano + int64(time.Since(epoch))
+}
+
+// Now returns the cached time of the cache group, recording the read. While the ticker is paused or stopped,
+// it returns the current time and wakes the ticker up.
+func (g *cacheGroup) Now() int64 {
+	if !g.read.Load() {
+		g.read.Store(true)
+	}
+	if now := g.now.Load(); now != 0 {
+		return now
+	}
+	g.wakeUp()
+	return monotonicNow()
+}
+
+// FakeClock is a Clock that only moves when told to, for testing expiry without sleeping.
+type FakeClock struct {
+	now atomic.Int64
+}
+
+// NewFakeClock creates a FakeClock set to the current time.
+func NewFakeClock() *FakeClock {
+	c := &FakeClock{}
+	c.now.Store(time.Now().UnixNano())
+	return c
+}
+
+// Now returns the time of the fake clock.
+func (c *FakeClock) Now() int64 {
+	return c.now.Load()
+}
+
+// Advance moves the fake clock forward by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.now.Add(int64(d))
+}
@@ -0,0 +1,106 @@
+package go_memoize
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	clock := NewFakeClock()
+	start := clock.Now()
+	clock.Advance(1500 * time.Millisecond)
+	if elapsed := clock.Now() - start; elapsed != int64(1500*time.Millisecond) {
+		t.Errorf("Expected %d, got %d", int64(1500*time.Millisecond), elapsed)
+	}
+}
+
+func TestCacheWithFakeClock(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](60, WithClock(clock))
+	cache.Set(1, 1)
+
+	clock.Advance(59 * time.Second)
+	if _, ok := cache.Get(1); !ok {
+		t.Errorf("Expected 1 to be cached")
+	}
+	clock.Advance(time.Second)
+	if _, ok := cache.Get(1); ok {
+		t.Errorf("Expected 1 to be expired")
+	}
+}
+
+func TestJanitorUsesCacheClock(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](60, WithClock(clock))
+	for i := 0; i < 10; i++ {
+		cache.Set(i, i)
+	}
+	cache.sweeper.sweep()
+	if n := cacheLen(cache); n != 10 {
+		t.Errorf("Expected 10, got %d", n)
+	}
+	clock.Advance(time.Minute)
+	cache.sweeper.sweep()
+	if n := cacheLen(cache); n != 0 {
+		t.Errorf("Expected 0, got %d", n)
+	}
+}
+
+func TestMonotonicNow(t *testing.T) {
+	first := monotonicNow()
+	if diff := time.Duration(time.Now().UnixNano() - first); diff < -time.Second || diff > time.Second {
+		t.Errorf("Expected the Unix time, got a difference of %v", diff)
+	}
+	if second := monotonicNow(); second < first {
+		t.Errorf("Expected %d to not be before %d", second, first)
+	}
+}
+
+func TestCacheGroupTickIntervalFollowsSmallestTTL(t *testing.T) {
+	g := newCacheGroup()
+	defer g.shutdown()
+	interval := func() time.Duration { return time.Duration(g.tickInterval.Load()) }
+
+	g.acquire(int64(160 * time.Millisecond))
+	g.acquire(int64(2 * time.Minute))
+	if interval() != 10*time.Millisecond {
+		t.Errorf("Expected %v, got %v", 10*time.Millisecond, interval())
+	}
+	g.acquire(int64(time.Microsecond))
+	if interval() != minTickInterval {
+		t.Errorf("Expected %v, got %v", minTickInterval, interval())
+	}
+	g.release(int64(time.Microsecond))
+	g.release(int64(160 * time.Millisecond))
+	if interval() != maxTickInterval {
+		t.Errorf("Expected %v, got %v", maxTickInterval, interval())
+	}
+}
+
+func TestCacheGroupPausesWhenIdle(t *testing.T) {
+	g := newCacheGroup()
+	defer g.shutdown()
+	g.acquire(int64(16 * time.Millisecond))
+
+	waitFor(t, func() bool { return g.now.Load() == 0 })
+	if now, expected := g.Now(), time.Now().UnixNano(); expected-now > int64(time.Millisecond) {
+		t.Errorf("Expected the current time while paused, got %v behind", time.Duration(expected-now))
+	}
+	waitFor(t, func() bool { return g.now.Load() != 0 })
+}
+
+func TestCacheGroupTicksWhileRead(t *testing.T) {
+	g := newCacheGroup()
+	defer g.shutdown()
+	g.acquire(int64(16 * time.Millisecond))
+
+	for deadline := time.Now().Add(idleTicks * 2 * time.Millisecond); time.Now().Before(deadline); {
+		if now := g.Now(); time.Now().UnixNano()-now > int64(20*time.Millisecond) {
+			t.Fatalf("Expected the clock to follow the time, got %v behind", time.Duration(time.Now().UnixNano()-now))
+		}
+		time.Sleep(100 * time.Microsecond)
+	}
+	if g.now.Load() == 0 {
+		t.Errorf("Expected the clock to keep ticking")
+	}
+}
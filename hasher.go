@@ -0,0 +1,158 @@
+package go_memoize
+
+import (
+	"hash/maphash"
+	"math/bits"
+)
+
+// Hasher hashes cache keys to select their shard and feed the eviction policy.
+// Keys are mixed one after the other into a running uint64 hash, starting from Seed.
+// Implementations must be safe for concurrent use.
+type Hasher interface {
+	// Seed returns the initial value of the running hash.
+	Seed() uint64
+	// HashUint64 mixes a fixed-size value into the running hash h.
+	HashUint64(h uint64, v uint64) uint64
+	// HashString mixes a string into the running hash h. It must delimit the string, e.g. by mixing its length first,
+	// so that ("ab", "c") and ("a", "bc") hash differently.
+	HashString(h uint64, s string) uint64
+}
+
+var (
+	// FNV1a hashes keys byte by byte with the FNV-1a algorithm. It is the default hasher.
+	FNV1a Hasher = fnv1a{}
+
+	// XXHashStyle hashes keys 8 bytes at a time, and long strings 32 bytes at a time, with the rounds of xxHash64.
+	// It is faster than FNV1a on long strings. Its hashes are not xxHash64 digests.
+	XXHashStyle Hasher = xxHashStyle{}
+)
+
+// fnv1a is the FNV1a hasher.
+type fnv1a struct{}
+
+func (fnv1a) Seed() uint64                         { return offset64 }
+func (fnv1a) HashUint64(h uint64, v uint64) uint64 { return hashUint64(h, v) }
+func (fnv1a) HashString(h uint64, s string) uint64 { return hashString(h, s) }
+
+// mapHasher hashes keys with hash/maphash.
+type mapHasher struct {
+	seed maphash.Seed
+}
+
+// NewMapHasher returns a Hasher built on hash/maphash with a random seed.
+// Its hashes differ from one hasher and one process to the next, so that callers cannot craft keys that
+// all fall in the same shard or skew the frequencies seen by the eviction policy.
+func NewMapHasher() Hasher {
+	return &mapHasher{seed: maphash.MakeSeed()}
+}
+
+func (m *mapHasher) Seed() uint64 {
+	return 0
+}
+
+func (m *mapHasher) HashUint64(h uint64, v uint64) uint64 {
+	var b [16]byte
+	putUint64(b[:8], h)
+	putUint64(b[8:], v)
+	return maphash.Bytes(m.seed, b[:])
+}
+
+func (m *mapHasher) HashString(h uint64, s string) uint64 {
+	return m.HashUint64(h, maphash.String(m.seed, s))
+}
+
+// putUint64 stores v in b, least significant byte first.
+func putUint64(b []byte, v uint64) {
+	_ = b[7]
+	b[0] = byte(v)
+	b[1] = byte(v >> 8)
+	b[2] = byte(v >> 16)
+	b[3] = byte(v >> 24)
+	b[4] = byte(v >> 32)
+	b[5] = byte(v >> 40)
+	b[6] = byte(v >> 48)
+	b[7] = byte(v >> 56)
+}
+
+// xxHash64 prime constants
+const (
+	xxPrime1 uint64 = 11400714785074694791
+	xxPrime2 uint64 = 14029467366897019727
+	xxPrime3 uint64 = 1609587929392839161
+	xxPrime4 uint64 = 9650029242287828579
+	xxPrime5 uint64 = 2870177450012600261
+)
+
+// xxHashStyle is the XXHashStyle hasher.
+type xxHashStyle struct{}
+
+func (xxHashStyle) Seed() uint64 { return xxPrime5 }
+
+func (xxHashStyle) HashUint64(h uint64, v uint64) uint64 {
+	return xxMerge(h, v)
+}
+
+func (xxHashStyle) HashString(h uint64, s string) uint64 {
+	h = xxMerge(h, uint64(len(s)))
+	if len(s) >= 32 {
+		v1 := h + xxPrime1 + xxPrime2
+		v2 := h + xxPrime2
+		v3 := h
+		v4 := h - xxPrime1
+		for ; len(s) >= 32; s = s[32:] {
+			v1 = xxRound(v1, readUint64(s[0:8]))
+			v2 = xxRound(v2, readUint64(s[8:16]))
+			v3 = xxRound(v3, readUint64(s[16:24]))
+			v4 = xxRound(v4, readUint64(s[24:32]))
+		}
+		h = bits.RotateLeft64(v1, 1) + bits.RotateLeft64(v2, 7) + bits.RotateLeft64(v3, 12) + bits.RotateLeft64(v4, 18)
+		h = xxMergeRound(h, v1)
+		h = xxMergeRound(h, v2)
+		h = xxMergeRound(h, v3)
+		h = xxMergeRound(h, v4)
+	}
+	for ; len(s) >= 8; s = s[8:] {
+		h = xxMerge(h, readUint64(s))
+	}
+	if len(s) >= 4 {
+		h ^= uint64(readUint32(s)) * xxPrime1
+		h = bits.RotateLeft64(h, 23)*xxPrime2 + xxPrime3
+		s = s[4:]
+	}
+	for ; len(s) > 0; s = s[1:] {
+		h ^= uint64(s[0]) * xxPrime5
+		h = bits.RotateLeft64(h, 11) * xxPrime1
+	}
+	return h
+}
+
+// xxRound mixes 8 bytes of input into an accumulator.
+func xxRound(acc, v uint64) uint64 {
+	acc += v * xxPrime2
+	return bits.RotateLeft64(acc, 31) * xxPrime1
+}
+
+// xxMergeRound merges an accumulator into the hash.
+func xxMergeRound(h, acc uint64) uint64 {
+	h ^= xxRound(0, acc)
+	return h*xxPrime1 + xxPrime4
+}
+
+// xxMerge mixes 8 bytes of input into the hash.
+func xxMerge(h, v uint64) uint64 {
+	h ^= xxRound(0, v)
+	return bits.RotateLeft64(h, 27)*xxPrime1 + xxPrime4
+}
+
+// readUint64 reads the first 8 bytes of s, least significant first.
+func readUint64(s string) uint64 {
+	_ = s[7]
+	return uint64(s[0]) | uint64(s[1])<<8 | uint64(s[2])<<16 | uint64(s[3])<<24 |
+		uint64(s[4])<<32 | uint64(s[5])<<40 | uint64(s[6])<<48 | uint64(s[7])<<56
+}
+
+// readUint32 reads the first 4 bytes of s, least significant first.
+func readUint32(s string) uint32 {
+	_ = s[3]
+	return uint32(s[0]) | uint32(s[1])<<8 | uint32(s[2])<<16 | uint32(s[3])<<24
+}
@@ -0,0 +1,105 @@
+package go_memoize
+
+import (
+	"strings"
+	"testing"
+)
+
+// testHashers returns the hashers shipped with the package.
+func testHashers() map[string]Hasher {
+	return map[string]Hasher{"FNV1a": FNV1a, "MapHasher": NewMapHasher(), "XXHashStyle": XXHashStyle}
+}
+
+func TestHashersAreDeterministic(t *testing.T) {
+	for name, hasher := range testHashers() {
+		if hash2(hasher, "a", 1) != hash2(hasher, "a", 1) {
+			t.Errorf("%s: Expected equal keys to hash equally", name)
+		}
+		if hash2(hasher, "a", 1) == hash2(hasher, "a", 2) {
+			t.Errorf("%s: Expected different keys to hash differently", name)
+		}
+	}
+}
+
+func TestHashersDelimitStrings(t *testing.T) {
+	long := strings.Repeat("x", 100)
+	for name, hasher := range testHashers() {
+		for _, c := range [][2][2]string{{{"ab", "c"}, {"a", "bc"}}, {{"", "a"}, {"a", ""}}, {{long, "y"}, {long[:99], "xy"}}} {
+			if hash2(hasher, c[0][0], c[0][1]) == hash2(hasher, c[1][0], c[1][1]) {
+				t.Errorf("%s: Expected (%q, %q) and (%q, %q) to hash differently", name, c[0][0], c[0][1], c[1][0], c[1][1])
+			}
+		}
+	}
+}
+
+func TestHashersLongStrings(t *testing.T) {
+	s := strings.Repeat("abcdefgh", 20)
+	for name, hasher := range testHashers() {
+		seen := make(map[uint64]int)
+		for n := 0; n <= len(s); n++ {
+			h := hash1(hasher, s[:n])
+			if prev, ok := seen[h]; ok {
+				t.Fatalf("%s: Expected prefixes of length %d and %d to hash differently", name, prev, n)
+			}
+			seen[h] = n
+		}
+		// flipping a single byte anywhere in a long string changes the hash
+		for i := 0; i < len(s); i++ {
+			flipped := s[:i] + "z" + s[i+1:]
+			if hash1(hasher, flipped) == hash1(hasher, s) {
+				t.Fatalf("%s: Expected a change at byte %d to change the hash", name, i)
+			}
+		}
+	}
+}
+
+func TestMapHasherIsSeeded(t *testing.T) {
+	h1, h2 := NewMapHasher(), NewMapHasher()
+	if hash1(h1, "key") == hash1(h2, "key") && hash1(h1, 42) == hash1(h2, 42) {
+		t.Errorf("Expected hashers with different seeds to hash differently")
+	}
+}
+
+func TestHashersSpreadKeysAcrossShards(t *testing.T) {
+	for name, hasher := range testHashers() {
+		counts := make([]int, 16)
+		for i := 0; i < 16000; i++ {
+			counts[hash1(hasher, i)&15]++
+		}
+		for shard, n := range counts {
+			if n < 800 || n > 1200 {
+				t.Errorf("%s: Expected about 1000 keys in shard %d, got %d", name, shard, n)
+			}
+		}
+	}
+}
+
+func TestMemoizeWithHasher(t *testing.T) {
+	for name, hasher := range testHashers() {
+		count := 0
+		memoizedFn := Memoize2(func(a string, b int) string {
+			count++
+			return strings.Repeat(a, b)
+		}, 0, WithHasher(hasher), WithShards(8), WithCapacity(100), WithEvictionPolicy(TinyLFU))
+		for i := 0; i < 3; i++ {
+			if v := memoizedFn("ab", 2); v != "abab" {
+				t.Errorf("%s: Expected abab, got %s", name, v)
+			}
+		}
+		if count != 1 {
+			t.Errorf("%s: Expected 1, got %d", name, count)
+		}
+	}
+}
+
+func TestNewCacheWithHasher(t *testing.T) {
+	hasher := NewMapHasher()
+	cache := NewCache[string, int](0, WithHasher(hasher), WithShards(4))
+	cache.Set("a", 1)
+	if v, ok := cache.Get("a"); !ok || v != 1 {
+		t.Errorf("Expected 1, got %d", v)
+	}
+	if s := cache.shardFor("a"); s != &cache.shards[hash1(hasher, "a")&3] {
+		t.Errorf("Expected the shard to be selected by the configured hasher")
+	}
+}
@@ -3,9 +3,10 @@ package go_memoize
 import (
 	"fmt"
 	"math"
+	"reflect"
 )
 
-// we are using FNV-1a hash algorithm to hash the key
+// by default we are using FNV-1a hash algorithm to hash the key, see FNV1a
 // FNV (Fowler-Noll-Vo) hash algorithm has two main components:
 // 1. offset basis
 // The offset basis is the starting value of the hash.
@@ -13,106 +14,154 @@ import (
 //The prime multiplier is a large prime number that is used to hash the input data.
 
 // how it works:
-// 1. The offset basis is multiplied by the prime multiplier.
-// 2. The result is XORed with the first byte of the input data.
+// 1. The hash starts as the offset basis.
+// 2. The hash is XORed with the first byte of the input data.
 // 3. The result is then multiplied by the prime multiplier.
 // 4. This process is repeated for each byte of the input data.
 // 5. The final result is the hash value.
 
+// Multiple keys are hashed as a single stream of bytes, one key after the other.
+// To keep the stream unambiguous, every key contributes a self-delimiting sequence of bytes:
+// fixed-size keys (numbers and booleans) always contribute 8 bytes, and strings are prefixed with their length,
+// so that ("ab", "c") and ("a", "bc") hash differently.
+
 const (
-	// FNV-1a hash constants for 32-bit and 64-bit architectures
+	// FNV-1a hash constants for 64-bit hashes
 	offset64 = uint64(14695981039346656037)
 	prime64  = uint64(1099511628211)
-
-	// for hashing boolean values
-	trueHash  = offset64 ^ 1*prime64
-	falseHash = offset64 ^ 0*prime64
 )
 
-// hash1 hashes a single key using the FNV-1a algorithm.
+// hash1 hashes a single key using the given hasher.
 // A is a comparable type.
-func hash1[A comparable](key A) uint64 {
-	return hash(offset64, key)
+func hash1[A comparable](hasher Hasher, key A) uint64 {
+	return hash(hasher, hasher.Seed(), key)
 }
 
-// hash2 hashes two keys using the FNV-1a algorithm.
+// hash2 hashes two keys using the given hasher.
 // A and B are comparable types.
-func hash2[A, B comparable](key1 A, key2 B) uint64 {
-	return hash(hash(offset64, key1), key2)
+func hash2[A, B comparable](hasher Hasher, key1 A, key2 B) uint64 {
+	return hash(hasher, hash(hasher, hasher.Seed(), key1), key2)
 }
 
-// hash3 hashes three keys using the FNV-1a algorithm.
+// hash3 hashes three keys using the given hasher.
 // A, B, and C are comparable types.
-func hash3[A, B, C comparable](key1 A, key2 B, key3 C) uint64 {
-	return hash(hash(hash(offset64, key1), key2), key3)
+func hash3[A, B, C comparable](hasher Hasher, key1 A, key2 B, key3 C) uint64 {
+	return hash(hasher, hash(hasher, hash(hasher, hasher.Seed(), key1), key2), key3)
 }
 
-// hash4 hashes four keys using the FNV-1a algorithm.
+// hash4 hashes four keys using the given hasher.
 // A, B, C, and D are comparable types.
-func hash4[A, B, C, D comparable](key1 A, key2 B, key3 C, key4 D) uint64 {
-	return hash(hash(hash(hash(offset64, key1), key2), key3), key4)
+func hash4[A, B, C, D comparable](hasher Hasher, key1 A, key2 B, key3 C, key4 D) uint64 {
+	return hash(hasher, hash(hasher, hash(hasher, hash(hasher, hasher.Seed(), key1), key2), key3), key4)
 }
 
-// hash5 hashes five keys using the FNV-1a algorithm.
+// hash5 hashes five keys using the given hasher.
 // A, B, C, D, and E are comparable types.
-func hash5[A, B, C, D, E comparable](key1 A, key2 B, key3 C, key4 D, key5 E) uint64 {
-	return hash(hash(hash(hash(hash(offset64, key1), key2), key3), key4), key5)
+func hash5[A, B, C, D, E comparable](hasher Hasher, key1 A, key2 B, key3 C, key4 D, key5 E) uint64 {
+	return hash(hasher, hash(hasher, hash(hasher, hash(hasher, hash(hasher, hasher.Seed(), key1), key2), key3), key4), key5)
 }
 
-// hash6 hashes six keys using the FNV-1a algorithm.
+// hash6 hashes six keys using the given hasher.
 // A, B, C, D, E, and F are comparable types.
-func hash6[A, B, C, D, E, F comparable](key1 A, key2 B, key3 C, key4 D, key5 E, key6 F) uint64 {
-	return hash(hash(hash(hash(hash(hash(offset64, key1), key2), key3), key4), key5), key6)
+func hash6[A, B, C, D, E, F comparable](hasher Hasher, key1 A, key2 B, key3 C, key4 D, key5 E, key6 F) uint64 {
+	return hash(hasher, hash(hasher, hash(hasher, hash(hasher, hash(hasher, hash(hasher, hasher.Seed(), key1), key2), key3), key4), key5), key6)
 }
 
-// hash7 hashes seven keys using the FNV-1a algorithm.
+// hash7 hashes seven keys using the given hasher.
 // A, B, C, D, E, F, and G are comparable types.
-func hash7[A, B, C, D, E, F, G comparable](key1 A, key2 B, key3 C, key4 D, key5 E, key6 F, key7 G) uint64 {
-	return hash(hash(hash(hash(hash(hash(hash(offset64, key1), key2), key3), key4), key5), key6), key7)
+func hash7[A, B, C, D, E, F, G comparable](hasher Hasher, key1 A, key2 B, key3 C, key4 D, key5 E, key6 F, key7 G) uint64 {
+	return hash(hasher, hash(hasher, hash(hasher, hash(hasher, hash(hasher, hash(hasher, hash(hasher, hasher.Seed(), key1), key2), key3), key4), key5), key6), key7)
 }
 
-// hash hashes a key using the FNV-1a algorithm.
+// hash mixes a key into the running hash h using the given hasher.
+// Numbers and booleans are mixed as a single uint64 and strings as a whole, so that every key is self-delimiting.
 // A is a comparable type.
-func hash[A comparable](hash uint64, key A) uint64 {
+func hash[A comparable](hasher Hasher, h uint64, key A) uint64 {
 	switch v := any(key).(type) {
 	case string:
-		return hashString(hash, v)
+		return hasher.HashString(h, v)
 	case int:
-		return hashInt(hash, uint64(v))
+		return hasher.HashUint64(h, uint64(v))
 	case int8:
-		return hashInt(hash, uint64(v))
+		return hasher.HashUint64(h, uint64(v))
 	case int16:
-		return hashInt(hash, uint64(v))
+		return hasher.HashUint64(h, uint64(v))
 	case int32:
-		return hashInt(hash, uint64(v))
+		return hasher.HashUint64(h, uint64(v))
 	case int64:
-		return hashInt(hash, uint64(v))
+		return hasher.HashUint64(h, uint64(v))
 	case uint:
-		return hashUint(hash, uint64(v))
+		return hasher.HashUint64(h, uint64(v))
 	case uint8:
-		return hashUint(hash, uint64(v))
+		return hasher.HashUint64(h, uint64(v))
 	case uint16:
-		return hashUint(hash, uint64(v))
+		return hasher.HashUint64(h, uint64(v))
 	case uint32:
-		return hashUint(hash, uint64(v))
+		return hasher.HashUint64(h, uint64(v))
 	case uint64:
-		return hashUint(hash, v)
+		return hasher.HashUint64(h, v)
 	case uintptr:
-		return hashUint(hash, uint64(v))
+		return hasher.HashUint64(h, uint64(v))
 	case float32:
-		return hashFloat(hash, math.Float64bits(float64(v)))
+		return hasher.HashUint64(h, floatBits(float64(v)))
 	case float64:
-		return hashFloat(hash, math.Float64bits(v))
+		return hasher.HashUint64(h, floatBits(v))
 	case bool:
-		return hashBool(v)
+		return hasher.HashUint64(h, boolBits(v))
 	default:
-		panic(fmt.Sprintf("unsupported type for caching %T", key))
+		return hashValue(hasher, h, reflect.ValueOf(key))
 	}
 }
 
-// hashString hashes a string key using the FNV-1a algorithm.
+// hashValue mixes any comparable value into the running hash h, walking its structure with reflection.
+// It is the slow path of hash for named types, arrays, structs, pointers, channels and interfaces.
+// Equal values hash equally; in particular pointers and channels hash by identity.
+func hashValue(hasher Hasher, h uint64, v reflect.Value) uint64 {
+	switch v.Kind() {
+	case reflect.String:
+		return hasher.HashString(h, v.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return hasher.HashUint64(h, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return hasher.HashUint64(h, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return hasher.HashUint64(h, floatBits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		return hasher.HashUint64(hasher.HashUint64(h, floatBits(real(c))), floatBits(imag(c)))
+	case reflect.Bool:
+		return hasher.HashUint64(h, boolBits(v.Bool()))
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return hasher.HashUint64(h, uint64(v.Pointer()))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			h = hashValue(hasher, h, v.Index(i))
+		}
+		return h
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			h = hashValue(hasher, h, v.Field(i))
+		}
+		return h
+	case reflect.Interface:
+		if v.IsNil() {
+			return hasher.HashUint64(h, 0)
+		}
+		v = v.Elem()
+		return hashValue(hasher, hasher.HashString(h, v.Type().String()), v)
+	case reflect.Invalid:
+		// the nil interface, when the key type itself is an interface
+		return hasher.HashUint64(h, 0)
+	default:
+		// Only reachable through an interface holding a value that is not comparable, which == rejects as well.
+		panic(fmt.Sprintf("hash of unhashable type %s", v.Type()))
+	}
+}
+
+// hashString hashes a string key using the FNV-1a algorithm, prefixed with its length.
 func hashString(hash uint64, key string) uint64 {
 	length := len(key)
+	hash = hashUint64(hash, uint64(length))
 
 	// for loop unrolling
 	// Process four characters at a time
@@ -129,25 +178,34 @@ func hashString(hash uint64, key string) uint64 {
 	return hash
 }
 
-// hashInt hashes an integer key using the FNV-1a algorithm.
-func hashInt(hash uint64, key uint64) uint64 {
-	return (hash ^ key) * prime64
-}
-
-// hashUint hashes an unsigned integer key using the FNV-1a algorithm.
-func hashUint(hash uint64, key uint64) uint64 {
-	return (hash ^ key) * prime64
+// hashUint64 hashes the 8 bytes of key, least significant first, using the FNV-1a algorithm.
+func hashUint64(hash uint64, key uint64) uint64 {
+	hash = (hash ^ (key & 0xff)) * prime64
+	hash = (hash ^ (key >> 8 & 0xff)) * prime64
+	hash = (hash ^ (key >> 16 & 0xff)) * prime64
+	hash = (hash ^ (key >> 24 & 0xff)) * prime64
+	hash = (hash ^ (key >> 32 & 0xff)) * prime64
+	hash = (hash ^ (key >> 40 & 0xff)) * prime64
+	hash = (hash ^ (key >> 48 & 0xff)) * prime64
+	hash = (hash ^ (key >> 56)) * prime64
+	return hash
 }
 
-// hashFloat hashes a floating-point key using the FNV-1a algorithm.
-func hashFloat(hash uint64, key uint64) uint64 {
-	return (hash ^ key) * prime64
+// boolBits returns 1 for true and 0 for false.
+func boolBits(key bool) uint64 {
+	if key {
+		return 1
+	}
+	return 0
 }
 
-// hashBool hashes a boolean key using the FNV-1a algorithm.
-func hashBool(key bool) uint64 {
-	if key {
-		return trueHash
+// floatBits returns the bits of a floating-point key, mapping -0 to +0 as they compare equal, and every NaN to the same bits.
+func floatBits(key float64) uint64 {
+	if key == 0 {
+		return 0
+	}
+	if key != key {
+		return math.Float64bits(math.NaN())
 	}
-	return falseHash
+	return math.Float64bits(key)
 }
@@ -2,20 +2,24 @@ package go_memoize
 
 import (
 	"math"
+	"math/rand"
 	"testing"
 )
 
 func TestHashBoolTest(t *testing.T) {
-	if hashBool(true) != trueHash {
-		t.Errorf("Expected %d, got %d", trueHash, hashBool(true))
+	if FNV1a.HashUint64(offset64, 1) != hash1(FNV1a, true) {
+		t.Errorf("Expected %d, got %d", hash1(FNV1a, true), FNV1a.HashUint64(offset64, 1))
 	}
-	if hashBool(false) != falseHash {
-		t.Errorf("Expected %d, got %d", falseHash, hashBool(false))
+	if FNV1a.HashUint64(offset64, 0) != hash1(FNV1a, false) {
+		t.Errorf("Expected %d, got %d", hash1(FNV1a, false), FNV1a.HashUint64(offset64, 0))
+	}
+	if hash1(FNV1a, true) == hash1(FNV1a, false) {
+		t.Errorf("Expected true and false to hash differently")
 	}
 }
 
 func TestHashStringTest(t *testing.T) {
-	expected := hash1("test")
+	expected := hash1(FNV1a, "test")
 	result := hashString(offset64, "test")
 	if result != expected {
 		t.Errorf("Expected %d, got %d", expected, result)
@@ -23,24 +27,24 @@ func TestHashStringTest(t *testing.T) {
 }
 
 func TestHashIntTest(t *testing.T) {
-	expected := hash1(12345)
-	result := hashInt(offset64, 12345)
+	expected := hash1(FNV1a, 12345)
+	result := FNV1a.HashUint64(offset64, 12345)
 	if result != expected {
 		t.Errorf("Expected %d, got %d", expected, result)
 	}
 }
 
 func TestHashUintTest(t *testing.T) {
-	expected := hash1(12345)
-	result := hashUint(offset64, 12345)
+	expected := hash1(FNV1a, 12345)
+	result := hash1(FNV1a, uint(12345))
 	if result != expected {
 		t.Errorf("Expected %d, got %d", expected, result)
 	}
 }
 
 func TestHashFloatTest(t *testing.T) {
-	expected := hash1(123.45)
-	result := hashFloat(offset64, math.Float64bits(123.45))
+	expected := hash1(FNV1a, 123.45)
+	result := FNV1a.HashUint64(offset64, math.Float64bits(123.45))
 	if result != expected {
 		t.Errorf("Expected %d, got %d", expected, result)
 	}
@@ -48,7 +52,7 @@ func TestHashFloatTest(t *testing.T) {
 
 func TestHash1Test(t *testing.T) {
 	expected := hashString(offset64, "test")
-	result := hash1("test")
+	result := hash1(FNV1a, "test")
 	if result != expected {
 		t.Errorf("Expected %d, got %d", expected, result)
 	}
@@ -56,7 +60,7 @@ func TestHash1Test(t *testing.T) {
 
 func TestHash2Test(t *testing.T) {
 	expected := hashString(hashString(offset64, "test1"), "test2")
-	result := hash2("test1", "test2")
+	result := hash2(FNV1a, "test1", "test2")
 	if result != expected {
 		t.Errorf("Expected %d, got %d", expected, result)
 	}
@@ -64,7 +68,7 @@ func TestHash2Test(t *testing.T) {
 
 func TestHash3Test(t *testing.T) {
 	expected := hashString(hashString(hashString(offset64, "test1"), "test2"), "test3")
-	result := hash3("test1", "test2", "test3")
+	result := hash3(FNV1a, "test1", "test2", "test3")
 	if result != expected {
 		t.Errorf("Expected %d, got %d", expected, result)
 	}
@@ -72,7 +76,7 @@ func TestHash3Test(t *testing.T) {
 
 func TestHash4Test(t *testing.T) {
 	expected := hashString(hashString(hashString(hashString(offset64, "test1"), "test2"), "test3"), "test4")
-	result := hash4("test1", "test2", "test3", "test4")
+	result := hash4(FNV1a, "test1", "test2", "test3", "test4")
 	if result != expected {
 		t.Errorf("Expected %d, got %d", expected, result)
 	}
@@ -80,7 +84,7 @@ func TestHash4Test(t *testing.T) {
 
 func TestHash5Test(t *testing.T) {
 	expected := hashString(hashString(hashString(hashString(hashString(offset64, "test1"), "test2"), "test3"), "test4"), "test5")
-	result := hash5("test1", "test2", "test3", "test4", "test5")
+	result := hash5(FNV1a, "test1", "test2", "test3", "test4", "test5")
 	if result != expected {
 		t.Errorf("Expected %d, got %d", expected, result)
 	}
@@ -88,7 +92,7 @@ func TestHash5Test(t *testing.T) {
 
 func TestHash6Test(t *testing.T) {
 	expected := hashString(hashString(hashString(hashString(hashString(hashString(offset64, "test1"), "test2"), "test3"), "test4"), "test5"), "test6")
-	result := hash6("test1", "test2", "test3", "test4", "test5", "test6")
+	result := hash6(FNV1a, "test1", "test2", "test3", "test4", "test5", "test6")
 	if result != expected {
 		t.Errorf("Expected %d, got %d", expected, result)
 	}
@@ -96,8 +100,183 @@ func TestHash6Test(t *testing.T) {
 
 func TestHash7Test(t *testing.T) {
 	expected := hashString(hashString(hashString(hashString(hashString(hashString(hashString(offset64, "test1"), "test2"), "test3"), "test4"), "test5"), "test6"), "test7")
-	result := hash7("test1", "test2", "test3", "test4", "test5", "test6", "test7")
+	result := hash7(FNV1a, "test1", "test2", "test3", "test4", "test5", "test6", "test7")
 	if result != expected {
 		t.Errorf("Expected %d, got %d", expected, result)
 	}
 }
+
+func TestHashBoolUsesRunningHash(t *testing.T) {
+	seen := make(map[uint64]int)
+	for x := 0; x < 1000; x++ {
+		h := hash2(FNV1a, x, true)
+		if prev, ok := seen[h]; ok {
+			t.Fatalf("Expected hash2(FNV1a, %d, true) and hash2(FNV1a, %d, true) to differ", prev, x)
+		}
+		seen[h] = x
+	}
+	if hash3(FNV1a, 1, true, 2) == hash3(FNV1a, 1, false, 2) {
+		t.Errorf("Expected the boolean to change the hash")
+	}
+}
+
+func TestHashStringsAreLengthDelimited(t *testing.T) {
+	cases := [][2][2]string{
+		{{"ab", "c"}, {"a", "bc"}},
+		{{"", "abc"}, {"abc", ""}},
+		{{"", ""}, {"", "\x00"}},
+		{{"a\x00", "b"}, {"a", "\x00b"}},
+	}
+	for _, c := range cases {
+		if hash2(FNV1a, c[0][0], c[0][1]) == hash2(FNV1a, c[1][0], c[1][1]) {
+			t.Errorf("Expected hash2(FNV1a, %q, %q) and hash2(FNV1a, %q, %q) to differ", c[0][0], c[0][1], c[1][0], c[1][1])
+		}
+	}
+	if hash3(FNV1a, "a", "", "b") == hash3(FNV1a, "a", "b", "") {
+		t.Errorf("Expected empty strings to be position dependent")
+	}
+}
+
+func TestHashFloatZero(t *testing.T) {
+	if hash1(FNV1a, 0.0) != hash1(FNV1a, math.Copysign(0, -1)) {
+		t.Errorf("Expected +0 and -0, which compare equal, to hash equally")
+	}
+	if hash1(FNV1a, float32(0)) != hash1(FNV1a, float32(math.Copysign(0, -1))) {
+		t.Errorf("Expected +0 and -0, which compare equal, to hash equally")
+	}
+}
+
+// checkNoCollisions hashes pairs of values drawn by gen and fails if two distinct pairs collide.
+func checkNoCollisions[A, B comparable](t *testing.T, hasher Hasher, name string, genA func(*rand.Rand) A, genB func(*rand.Rand) B) {
+	t.Helper()
+	r := rand.New(rand.NewSource(1))
+	seen := make(map[uint64][2]any)
+	for i := 0; i < 5000; i++ {
+		a, b := genA(r), genB(r)
+		h := hash2(hasher, a, b)
+		if prev, ok := seen[h]; ok && (prev[0] != any(a) || prev[1] != any(b)) {
+			t.Fatalf("%s: hash2(%v, %v) collides with hash2(%v, %v)", name, a, b, prev[0], prev[1])
+		}
+		seen[h] = [2]any{a, b}
+	}
+}
+
+func TestHash2NoCollisionsAcrossTypes(t *testing.T) {
+	for name, hasher := range testHashers() {
+		t.Run(name, func(t *testing.T) {
+			small := func(r *rand.Rand) int { return r.Intn(100) - 50 }
+			checkNoCollisions(t, hasher, "int,bool", small, func(r *rand.Rand) bool { return r.Intn(2) == 0 })
+			checkNoCollisions(t, hasher, "bool,int", func(r *rand.Rand) bool { return r.Intn(2) == 0 }, small)
+			checkNoCollisions(t, hasher, "int8,int16", func(r *rand.Rand) int8 { return int8(r.Intn(256)) }, func(r *rand.Rand) int16 { return int16(r.Intn(1 << 16)) })
+			checkNoCollisions(t, hasher, "int32,int64", func(r *rand.Rand) int32 { return int32(r.Uint32()) }, func(r *rand.Rand) int64 { return int64(r.Uint64()) })
+			checkNoCollisions(t, hasher, "uint,uint8", func(r *rand.Rand) uint { return uint(r.Uint64()) }, func(r *rand.Rand) uint8 { return uint8(r.Intn(256)) })
+			checkNoCollisions(t, hasher, "uint16,uint32", func(r *rand.Rand) uint16 { return uint16(r.Intn(1 << 16)) }, func(r *rand.Rand) uint32 { return r.Uint32() })
+			checkNoCollisions(t, hasher, "uint64,uintptr", func(r *rand.Rand) uint64 { return r.Uint64() % 100 }, func(r *rand.Rand) uintptr { return uintptr(r.Intn(100)) })
+			checkNoCollisions(t, hasher, "float32,float64", func(r *rand.Rand) float32 { return float32(r.Intn(100)) / 4 }, func(r *rand.Rand) float64 { return float64(r.Intn(100)) / 8 })
+			checkNoCollisions(t, hasher, "string,string", randomString, randomString)
+			checkNoCollisions(t, hasher, "string,int", randomString, small)
+		})
+	}
+}
+
+func randomString(r *rand.Rand) string {
+	b := make([]byte, r.Intn(4))
+	for i := range b {
+		b[i] = "ab\x00"[r.Intn(3)]
+	}
+	return string(b)
+}
+
+func FuzzHash2Strings(f *testing.F) {
+	f.Add("ab", "c", "a", "bc")
+	f.Add("", "x", "x", "")
+	f.Fuzz(func(t *testing.T, a1, b1, a2, b2 string) {
+		if a1 == a2 && b1 == b2 {
+			return
+		}
+		if a1+b1 == a2+b2 && hash2(FNV1a, a1, b1) == hash2(FNV1a, a2, b2) {
+			t.Errorf("Expected hash2(FNV1a, %q, %q) and hash2(FNV1a, %q, %q) to differ", a1, b1, a2, b2)
+		}
+	})
+}
+
+func FuzzHash2IntBool(f *testing.F) {
+	f.Add(int64(1), true, int64(2), true)
+	f.Add(int64(0), false, int64(0), true)
+	f.Fuzz(func(t *testing.T, x1 int64, b1 bool, x2 int64, b2 bool) {
+		if x1 == x2 && b1 == b2 {
+			return
+		}
+		if hash2(FNV1a, x1, b1) == hash2(FNV1a, x2, b2) {
+			t.Errorf("Expected hash2(FNV1a, %d, %t) and hash2(FNV1a, %d, %t) to differ", x1, b1, x2, b2)
+		}
+		if hash2(FNV1a, b1, x1) == hash2(FNV1a, b2, x2) {
+			t.Errorf("Expected hash2(FNV1a, %t, %d) and hash2(FNV1a, %t, %d) to differ", b1, x1, b2, x2)
+		}
+	})
+}
+
+type userID string
+
+type point struct {
+	x, y int
+	tag  string
+}
+
+type nested struct {
+	p     point
+	ids   [2]userID
+	ptr   *int
+	value any
+	c     complex128
+}
+
+func TestHashNamedAndCompositeTypes(t *testing.T) {
+	one, two := 1, 1
+	ch := make(chan int)
+	cases := []struct {
+		name     string
+		same     [2]uint64
+		distinct [2]uint64
+	}{
+		{"named string", [2]uint64{hash1(FNV1a, userID("a")), hash1(FNV1a, userID("a"))}, [2]uint64{hash1(FNV1a, userID("a")), hash1(FNV1a, userID("b"))}},
+		{"array", [2]uint64{hash1(FNV1a, [3]int{1, 2, 3}), hash1(FNV1a, [3]int{1, 2, 3})}, [2]uint64{hash1(FNV1a, [3]int{1, 2, 3}), hash1(FNV1a, [3]int{1, 3, 2})}},
+		{"struct", [2]uint64{hash1(FNV1a, point{1, 2, "a"}), hash1(FNV1a, point{1, 2, "a"})}, [2]uint64{hash1(FNV1a, point{1, 2, "a"}), hash1(FNV1a, point{2, 1, "a"})}},
+		{"pointer", [2]uint64{hash1(FNV1a, &one), hash1(FNV1a, &one)}, [2]uint64{hash1(FNV1a, &one), hash1(FNV1a, &two)}},
+		{"channel", [2]uint64{hash1(FNV1a, ch), hash1(FNV1a, ch)}, [2]uint64{hash1(FNV1a, ch), hash1(FNV1a, make(chan int))}},
+		{"interface", [2]uint64{hash1(FNV1a, any(1)), hash1(FNV1a, any(1))}, [2]uint64{hash1(FNV1a, any(1)), hash1(FNV1a, any("1"))}},
+		{"nil interface", [2]uint64{hash1(FNV1a, any(nil)), hash1(FNV1a, any(nil))}, [2]uint64{hash1(FNV1a, any(nil)), hash1(FNV1a, any(1))}},
+		{"nested", [2]uint64{
+			hash1(FNV1a, nested{point{1, 2, "a"}, [2]userID{"x", "y"}, &one, 1, 1 + 2i}),
+			hash1(FNV1a, nested{point{1, 2, "a"}, [2]userID{"x", "y"}, &one, 1, 1 + 2i}),
+		}, [2]uint64{
+			hash1(FNV1a, nested{point{1, 2, "a"}, [2]userID{"x", "y"}, &one, 1, 1 + 2i}),
+			hash1(FNV1a, nested{point{1, 2, "a"}, [2]userID{"x", "y"}, &one, int64(1), 1 + 2i}),
+		}},
+		{"struct strings", [2]uint64{hash1(FNV1a, point{tag: ""}), hash1(FNV1a, point{tag: ""})}, [2]uint64{hash2(FNV1a, point{tag: "ab"}, "c"), hash2(FNV1a, point{tag: "a"}, "bc")}},
+	}
+	for _, c := range cases {
+		if c.same[0] != c.same[1] {
+			t.Errorf("%s: Expected equal values to hash equally", c.name)
+		}
+		if c.distinct[0] == c.distinct[1] {
+			t.Errorf("%s: Expected distinct values to hash differently", c.name)
+		}
+	}
+}
+
+func TestHashNegativeZeroInStruct(t *testing.T) {
+	type coords struct{ lat, lng float64 }
+	if hash1(FNV1a, coords{0, 1}) != hash1(FNV1a, coords{math.Copysign(0, -1), 1}) {
+		t.Errorf("Expected +0 and -0, which compare equal, to hash equally")
+	}
+}
+
+func TestHashUncomparableInterfaceValuePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected hashing a slice held by an interface to panic")
+		}
+	}()
+	hash1(FNV1a, any([]int{1}))
+}
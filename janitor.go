@@ -0,0 +1,212 @@
+package go_memoize
+
+import (
+	"runtime"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// sweepBatchSize is the number of entries of a shard the janitor inspects while holding its lock.
+	sweepBatchSize = 128
+	// maxSweepRounds bounds the number of batches the janitor inspects per shard and sweep.
+	maxSweepRounds = 16
+)
+
+// sweeper removes expired entries of one cache on behalf of the cacheGroup janitor.
+// refresh, if not nil, starts the refreshes of the entries due for refresh-ahead and returns the interval
+// until it should run again; the janitor runs it on ticks rather than sweeps, at the earliest at nextRefresh.
+type sweeper struct {
+	sweep       func()
+	refresh     func() time.Duration
+	nextRefresh atomic.Int64
+}
+
+// register adds a sweeper run by the janitor on every sweep interval.
+func (g *cacheGroup) register(s *sweeper) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.sweepers[s] = struct{}{}
+	if s.refresh != nil {
+		var refreshers []*sweeper
+		if old := g.refreshers.Load(); old != nil {
+			refreshers = append(refreshers, *old...)
+		}
+		refreshers = append(refreshers, s)
+		g.refreshers.Store(&refreshers)
+	}
+}
+
+// unregister removes a sweeper added by register.
+func (g *cacheGroup) unregister(s *sweeper) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	delete(g.sweepers, s)
+	if old := g.refreshers.Load(); s.refresh != nil && old != nil {
+		refreshers := slices.DeleteFunc(slices.Clone(*old), func(r *sweeper) bool { return r == s })
+		g.refreshers.Store(&refreshers)
+	}
+}
+
+// sweep runs all registered sweepers.
+func (g *cacheGroup) sweep() {
+	g.mu.Lock()
+	sweepers := make([]*sweeper, 0, len(g.sweepers))
+	for s := range g.sweepers {
+		sweepers = append(sweepers, s)
+	}
+	g.mu.Unlock()
+
+	for _, s := range sweepers {
+		s.sweep()
+	}
+}
+
+// refreshAhead runs the refreshers whose interval has passed at now.
+func (g *cacheGroup) refreshAhead(now int64) {
+	refreshers := g.refreshers.Load()
+	if refreshers == nil {
+		return
+	}
+	for _, s := range *refreshers {
+		if now >= s.nextRefresh.Load() {
+			s.nextRefresh.Store(now + int64(s.refresh()))
+		}
+	}
+}
+
+// startJanitor returns a Cache wrapping c, acquiring the group of c and registering c with its janitor
+// when its entries can expire. The sweeper only references the cache state, so the Cache itself can still be
+// garbage collected, at which point a finalizer closes it.
+func startJanitor[K comparable, V any](c *cache[K, V]) *Cache[K, V] {
+	wrapper := &Cache[K, V]{c}
+	c.tickTTL.Store(c.clockTTL())
+	c.cacheGroup.acquire(c.tickTTL.Load())
+	if c.ttl != 0 || c.errorTTL != 0 {
+		c.registerSweeper()
+	}
+	runtime.SetFinalizer(wrapper, func(wrapper *Cache[K, V]) {
+		wrapper.close()
+	})
+	return wrapper
+}
+
+// registerSweeper registers the cache with the janitor of its group. The lifecycle lock must be held,
+// unless the cache is being created.
+func (c *cache[K, V]) registerSweeper() {
+	c.sweeper = &sweeper{sweep: c.sweep}
+	if c.refreshAhead > 0 {
+		c.sweeper.refresh = c.refresh
+	}
+	c.cacheGroup.register(c.sweeper)
+	c.expiring.Store(true)
+}
+
+// expireWithin prepares the cache for an entry expiring after ttl, other than the TTL of the cache:
+// it registers the cache with the janitor, if not yet, and makes the clock of its group precise enough for ttl.
+func (c *cache[K, V]) expireWithin(ttl int64) {
+	if tickTTL := c.tickTTL.Load(); c.expiring.Load() && (c.clock != nil || (tickTTL != 0 && tickTTL <= ttl)) {
+		return
+	}
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
+	if c.closed.Load() {
+		return
+	}
+	if c.sweeper == nil {
+		c.registerSweeper()
+	}
+	if tickTTL := c.tickTTL.Load(); c.clock == nil && (tickTTL == 0 || ttl < tickTTL) {
+		c.cacheGroup.retune(tickTTL, ttl)
+		c.tickTTL.Store(ttl)
+	}
+}
+
+// Close stops the background work of the cache and waits for the refreshes in flight to complete.
+// The cache remains usable, but its expired entries are no longer removed by the janitor nor refreshed ahead of expiry.
+// Caches that are not closed are closed once garbage collected.
+func (c *Cache[K, V]) Close() {
+	runtime.SetFinalizer(c, nil)
+	c.close()
+	c.refreshes.Wait()
+}
+
+// close unregisters the cache from the janitor and releases its group, once.
+func (c *cache[K, V]) close() {
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
+	if !c.closed.CompareAndSwap(false, true) {
+		return
+	}
+	if c.sweeper != nil {
+		c.cacheGroup.unregister(c.sweeper)
+	}
+	c.cacheGroup.release(c.tickTTL.Load())
+}
+
+// clockTTL returns the smallest TTL of the cache for which the clock of its group must be precise, or 0 if none,
+// as when the cache has its own clock. Entries with their own TTL may lower it, see expireWithin.
+func (c *cache[K, V]) clockTTL() int64 {
+	if c.clock != nil {
+		return 0
+	}
+	if c.ttl == 0 || (c.errorTTL != 0 && c.errorTTL < c.ttl) {
+		return c.errorTTL
+	}
+	return c.ttl
+}
+
+// sweep removes the expired entries of the cache.
+func (c *cache[K, V]) sweep() {
+	if c.closed.Load() {
+		return
+	}
+	// entries are kept until their stale window has passed too
+	if removed := sweepExpired(c.shards, c.now()-c.staleTTL); removed > 0 && c.listeners.active() {
+		// delivered on another goroutine, so that slow listeners hold up neither the janitor nor the clock of the group
+		go c.dispatchAll()
+	}
+}
+
+// sweepExpired removes expired entries from the shards and returns the number of removed entries, whose expiry events
+// are left queued. Each shard is sampled in batches of sweepBatchSize entries, releasing the lock between batches,
+// and sampling continues while more than a quarter of a batch was expired.
+func sweepExpired[K comparable, V any](shards []shard[K, V], now int64) int {
+	total := 0
+	for i := range shards {
+		s := &shards[i]
+		for round := 0; round < maxSweepRounds; round++ {
+			removed := s.sweepBatch(now)
+			total += removed
+			if removed <= sweepBatchSize/4 {
+				break
+			}
+		}
+	}
+	return total
+}
+
+// sweepBatch removes the expired entries among up to sweepBatchSize entries of the shard
+// and returns the number of removed entries. Map iteration starts at a random position,
+// so successive batches sample different entries.
+func (s *shard[K, V]) sweepBatch(now int64) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.policy != nil {
+		s.drainReads()
+	}
+	inspected, removed := 0, 0
+	for key, e := range s.entries {
+		if !e.alive(now) {
+			s.remove(key, EventExpire)
+			removed++
+		}
+		if inspected++; inspected == sweepBatchSize {
+			break
+		}
+	}
+	s.stats.expirations.Add(uint64(removed))
+	return removed
+}
@@ -0,0 +1,206 @@
+package go_memoize
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestSweepExpired(t *testing.T) {
+	cache := NewCache[int, int](10, WithShards(4))
+	for i := 0; i < 1000; i++ {
+		cache.Set(i, i)
+	}
+	now := cache.now()
+
+	sweepExpired(cache.shards, now)
+	if n := cacheLen(cache); n != 1000 {
+		t.Errorf("Expected 1000, got %d", n)
+	}
+
+	for round := 0; round < 10 && cacheLen(cache) > 0; round++ {
+		sweepExpired(cache.shards, now+int64(10*time.Second))
+	}
+	if n := cacheLen(cache); n != 0 {
+		t.Errorf("Expected 0, got %d", n)
+	}
+}
+
+func TestSweepBatchIsBounded(t *testing.T) {
+	cache := NewCache[int, int](10)
+	for i := 0; i < 1000; i++ {
+		cache.Set(i, i)
+	}
+	if removed := cache.shards[0].sweepBatch(cache.now() + int64(10*time.Second)); removed != sweepBatchSize {
+		t.Errorf("Expected %d, got %d", sweepBatchSize, removed)
+	}
+}
+
+func TestSweepExpiredWithCapacity(t *testing.T) {
+	cache := NewCache[int, int](10, WithCapacity(100))
+	for i := 0; i < 100; i++ {
+		cache.Set(i, i)
+		cache.Get(i)
+	}
+	sweepExpired(cache.shards, cache.now()+int64(10*time.Second))
+	s := &cache.shards[0]
+	if len(s.entries) != 0 || s.policy.len() != 0 {
+		t.Errorf("Expected 0, got %d entries and %d tracked", len(s.entries), s.policy.len())
+	}
+}
+
+func TestJanitorRemovesExpiredEntries(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](1, WithClock(clock))
+	for i := 0; i < 100; i++ {
+		cache.Set(i, i)
+	}
+	cache.sweeper.sweep()
+	if n := cacheLen(cache); n != 100 {
+		t.Errorf("Expected 100, got %d", n)
+	}
+	clock.Advance(2 * time.Second)
+	cache.sweeper.sweep()
+	if n := cacheLen(cache); n != 0 {
+		t.Errorf("Expected 0, got %d", n)
+	}
+}
+
+func TestJanitorIgnoresCachesWithoutTTL(t *testing.T) {
+	if cache := NewCache[int, int](0); cache.sweeper != nil {
+		t.Errorf("Expected no sweeper for a cache without TTL")
+	}
+}
+
+func TestJanitorUnregistersCollectedCaches(t *testing.T) {
+	cache := NewCache[int, int](10)
+	s := cache.sweeper
+	if !isRegistered(s) {
+		t.Fatalf("Expected the sweeper to be registered")
+	}
+	cache = nil
+	for i := 0; i < 10 && isRegistered(s); i++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if isRegistered(s) {
+		t.Errorf("Expected the sweeper of a collected cache to be unregistered")
+	}
+}
+
+func TestJanitorUnregistersCollectedMemoizersWithRefreshAhead(t *testing.T) {
+	m := NewMemoizer1(func(k int) int { return k }, time.Minute, WithRefreshAhead(0.8, 1))
+	m.Call(1)
+	s := m.cache.sweeper
+	m = nil
+	for i := 0; i < 10 && isRegistered(s); i++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if isRegistered(s) {
+		t.Errorf("Expected the sweeper of a collected memoizer to be unregistered")
+	}
+}
+
+// cacheLen returns the number of entries stored in the cache, expired or not.
+func cacheLen[K comparable, V any](c *Cache[K, V]) int {
+	n := 0
+	for i := range c.shards {
+		c.shards[i].mu.RLock()
+		n += len(c.shards[i].entries)
+		c.shards[i].mu.RUnlock()
+	}
+	return n
+}
+
+func isRegistered(s *sweeper) bool {
+	cacheGroupInstance.mu.Lock()
+	defer cacheGroupInstance.mu.Unlock()
+	_, ok := cacheGroupInstance.sweepers[s]
+	return ok
+}
+
+func TestCacheGroupRunsWhileAcquired(t *testing.T) {
+	g := newCacheGroup()
+	if g.done != nil {
+		t.Fatalf("Expected the ticker to start with the first cache")
+	}
+	g.acquire(0)
+	g.acquire(0)
+	stopped := g.stopped
+	if g.done == nil || g.now.Load() == 0 {
+		t.Fatalf("Expected the ticker to run")
+	}
+	g.release(0)
+	if g.done == nil {
+		t.Fatalf("Expected the ticker to run while a cache uses the group")
+	}
+	g.release(0)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the ticker goroutine to return")
+	}
+	if g.now.Load() != 0 {
+		t.Errorf("Expected the cached time to be cleared")
+	}
+	if now, expected := g.Now(), time.Now().UnixNano(); expected-now > int64(time.Second) {
+		t.Errorf("Expected the current time while stopped, got %d", now)
+	}
+}
+
+func TestCacheClose(t *testing.T) {
+	refs := groupRefs()
+	cache := NewCache[int, int](10)
+	if groupRefs() != refs+1 {
+		t.Fatalf("Expected %d, got %d", refs+1, groupRefs())
+	}
+	s := cache.sweeper
+	cache.Close()
+	cache.Close()
+	if groupRefs() != refs {
+		t.Errorf("Expected %d, got %d", refs, groupRefs())
+	}
+	if isRegistered(s) {
+		t.Errorf("Expected the sweeper of a closed cache to be unregistered")
+	}
+	cache.Set(1, 1)
+	if value, ok := cache.Get(1); !ok || value != 1 {
+		t.Errorf("Expected 1, got %d", value)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	cache := NewCache[int, int](10)
+	defer cache.Close()
+	Shutdown()
+	if groupRunning() {
+		t.Fatalf("Expected the ticker to be stopped")
+	}
+	if now := cache.NowUnix(); now != time.Now().Unix() && now != time.Now().Unix()-1 {
+		t.Errorf("Expected the current time, got %d", now)
+	}
+	cache.Set(1, 1)
+	if value, ok := cache.Get(1); !ok || value != 1 {
+		t.Errorf("Expected 1, got %d", value)
+	}
+	Shutdown()
+
+	restarted := NewCache[int, int](10)
+	defer restarted.Close()
+	if !groupRunning() {
+		t.Errorf("Expected a new cache to start the ticker again")
+	}
+}
+
+func groupRefs() int {
+	cacheGroupInstance.mu.Lock()
+	defer cacheGroupInstance.mu.Unlock()
+	return cacheGroupInstance.refs
+}
+
+func groupRunning() bool {
+	cacheGroupInstance.mu.Lock()
+	defer cacheGroupInstance.mu.Unlock()
+	return cacheGroupInstance.done != nil
+}
@@ -0,0 +1,134 @@
+package go_memoize
+
+// Memoized functions with several arguments key their cache by the tuple of arguments rather than by their hash,
+// so that argument tuples whose hashes collide never share a result: the cache map compares the full tuple on lookup.
+// The hash is computed once per call and kept in the tuple to select the shard and feed the eviction policy;
+// it is skipped, and left zero, when the cache uses neither, see Cache.keyHasher.
+
+// hashedKey is a cache key carrying its precomputed hash.
+type hashedKey interface {
+	comparable
+	keyHash() uint64
+}
+
+// hashOf returns the precomputed hash of a hashed key.
+func hashOf[K hashedKey](key K) uint64 {
+	return key.keyHash()
+}
+
+// args2 is the cache key of a memoized function with two arguments.
+type args2[K1, K2 comparable] struct {
+	hash uint64
+	k1   K1
+	k2   K2
+}
+
+func newArgs2[K1, K2 comparable](hasher Hasher, k1 K1, k2 K2) args2[K1, K2] {
+	a := args2[K1, K2]{0, k1, k2}
+	if hasher != nil {
+		a.hash = hash2(hasher, k1, k2)
+	}
+	return a
+}
+
+func (a args2[K1, K2]) keyHash() uint64 { return a.hash }
+
+// args3 is the cache key of a memoized function with three arguments.
+type args3[K1, K2, K3 comparable] struct {
+	hash uint64
+	k1   K1
+	k2   K2
+	k3   K3
+}
+
+func newArgs3[K1, K2, K3 comparable](hasher Hasher, k1 K1, k2 K2, k3 K3) args3[K1, K2, K3] {
+	a := args3[K1, K2, K3]{0, k1, k2, k3}
+	if hasher != nil {
+		a.hash = hash3(hasher, k1, k2, k3)
+	}
+	return a
+}
+
+func (a args3[K1, K2, K3]) keyHash() uint64 { return a.hash }
+
+// args4 is the cache key of a memoized function with four arguments.
+type args4[K1, K2, K3, K4 comparable] struct {
+	hash uint64
+	k1   K1
+	k2   K2
+	k3   K3
+	k4   K4
+}
+
+func newArgs4[K1, K2, K3, K4 comparable](hasher Hasher, k1 K1, k2 K2, k3 K3, k4 K4) args4[K1, K2, K3, K4] {
+	a := args4[K1, K2, K3, K4]{0, k1, k2, k3, k4}
+	if hasher != nil {
+		a.hash = hash4(hasher, k1, k2, k3, k4)
+	}
+	return a
+}
+
+func (a args4[K1, K2, K3, K4]) keyHash() uint64 { return a.hash }
+
+// args5 is the cache key of a memoized function with five arguments.
+type args5[K1, K2, K3, K4, K5 comparable] struct {
+	hash uint64
+	k1   K1
+	k2   K2
+	k3   K3
+	k4   K4
+	k5   K5
+}
+
+func newArgs5[K1, K2, K3, K4, K5 comparable](hasher Hasher, k1 K1, k2 K2, k3 K3, k4 K4, k5 K5) args5[K1, K2, K3, K4, K5] {
+	a := args5[K1, K2, K3, K4, K5]{0, k1, k2, k3, k4, k5}
+	if hasher != nil {
+		a.hash = hash5(hasher, k1, k2, k3, k4, k5)
+	}
+	return a
+}
+
+func (a args5[K1, K2, K3, K4, K5]) keyHash() uint64 { return a.hash }
+
+// args6 is the cache key of a memoized function with six arguments.
+type args6[K1, K2, K3, K4, K5, K6 comparable] struct {
+	hash uint64
+	k1   K1
+	k2   K2
+	k3   K3
+	k4   K4
+	k5   K5
+	k6   K6
+}
+
+func newArgs6[K1, K2, K3, K4, K5, K6 comparable](hasher Hasher, k1 K1, k2 K2, k3 K3, k4 K4, k5 K5, k6 K6) args6[K1, K2, K3, K4, K5, K6] {
+	a := args6[K1, K2, K3, K4, K5, K6]{0, k1, k2, k3, k4, k5, k6}
+	if hasher != nil {
+		a.hash = hash6(hasher, k1, k2, k3, k4, k5, k6)
+	}
+	return a
+}
+
+func (a args6[K1, K2, K3, K4, K5, K6]) keyHash() uint64 { return a.hash }
+
+// args7 is the cache key of a memoized function with seven arguments.
+type args7[K1, K2, K3, K4, K5, K6, K7 comparable] struct {
+	hash uint64
+	k1   K1
+	k2   K2
+	k3   K3
+	k4   K4
+	k5   K5
+	k6   K6
+	k7   K7
+}
+
+func newArgs7[K1, K2, K3, K4, K5, K6, K7 comparable](hasher Hasher, k1 K1, k2 K2, k3 K3, k4 K4, k5 K5, k6 K6, k7 K7) args7[K1, K2, K3, K4, K5, K6, K7] {
+	a := args7[K1, K2, K3, K4, K5, K6, K7]{0, k1, k2, k3, k4, k5, k6, k7}
+	if hasher != nil {
+		a.hash = hash7(hasher, k1, k2, k3, k4, k5, k6, k7)
+	}
+	return a
+}
+
+func (a args7[K1, K2, K3, K4, K5, K6, K7]) keyHash() uint64 { return a.hash }
@@ -0,0 +1,91 @@
+package go_memoize
+
+import (
+	"testing"
+)
+
+// collidingHasher hashes every key to the same value, so that all argument tuples collide.
+type collidingHasher struct{}
+
+func (collidingHasher) Seed() uint64                         { return 42 }
+func (collidingHasher) HashUint64(h uint64, _ uint64) uint64 { return h }
+func (collidingHasher) HashString(h uint64, _ string) uint64 { return h }
+
+func TestMemoize2_CollidingHashesDoNotShareResults(t *testing.T) {
+	computeFn := func(a, b string) string {
+		return a + "|" + b
+	}
+	for _, opts := range [][]Option{nil, {WithShards(8)}, {WithCapacity(10), WithEvictionPolicy(TinyLFU)}} {
+		memoizedFn := Memoize2(computeFn, 0, append(opts, WithHasher(collidingHasher{}))...)
+		if v := memoizedFn("ab", "c"); v != "ab|c" {
+			t.Errorf("Expected ab|c, got %s", v)
+		}
+		if v := memoizedFn("a", "bc"); v != "a|bc" {
+			t.Errorf("Expected a|bc, got %s", v)
+		}
+	}
+}
+
+func TestMemoize3_CollidingBoolHashesDoNotShareResults(t *testing.T) {
+	count := 0
+	computeFn := func(a, b int, c bool) int {
+		count++
+		return a + b
+	}
+	memoizedFn := Memoize3(computeFn, 0, WithShards(4), WithHasher(collidingHasher{}))
+	if v := memoizedFn(1, 2, true); v != 3 {
+		t.Errorf("Expected 3, got %d", v)
+	}
+	if v := memoizedFn(10, 20, true); v != 30 {
+		t.Errorf("Expected 30, got %d", v)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+}
+
+func FuzzCollidingKeysDoNotLeak(f *testing.F) {
+	f.Add("ab", "c", "a", "bc")
+	f.Add("", "", "x", "")
+	f.Add("same", "key", "same", "key")
+	f.Fuzz(func(t *testing.T, a1, b1, a2, b2 string) {
+		cache := newCache[args2[string, string], string](0, 0, hashOf[args2[string, string]], WithShards(4), WithCapacity(64))
+		// Force both tuples onto the same hash, as a collision would.
+		key1 := args2[string, string]{hash: 42, k1: a1, k2: b1}
+		key2 := args2[string, string]{hash: 42, k1: a2, k2: b2}
+
+		v1 := cache.GetOrCompute(key1, func() string { return a1 + "\x00" + b1 })
+		v2 := cache.GetOrCompute(key2, func() string { return a2 + "\x00" + b2 })
+		if v1 != a1+"\x00"+b1 {
+			t.Errorf("Expected %q, got %q", a1+"\x00"+b1, v1)
+		}
+		if a1 == a2 && b1 == b2 {
+			if v2 != v1 {
+				t.Errorf("Expected equal arguments to share %q, got %q", v1, v2)
+			}
+			return
+		}
+		if v2 != a2+"\x00"+b2 {
+			t.Errorf("Expected %q, got %q", a2+"\x00"+b2, v2)
+		}
+		if v, _ := cache.Get(key1); v != v1 {
+			t.Errorf("Expected %q, got %q", v1, v)
+		}
+	})
+}
+
+func FuzzMemoize2MatchesComputeFn(f *testing.F) {
+	f.Add("ab", "c", "a", "bc")
+	f.Add("1", "23", "12", "3")
+	computeFn := func(a, b string) string {
+		return a + "\x00" + b
+	}
+	memoizedFn := Memoize2(computeFn, 0, WithShards(16))
+	f.Fuzz(func(t *testing.T, a1, b1, a2, b2 string) {
+		for _, args := range [][2]string{{a1, b1}, {a2, b2}, {a1, b1}} {
+			if v, expected := memoizedFn(args[0], args[1]), computeFn(args[0], args[1]); v != expected {
+				t.Errorf("Expected %q, got %q", expected, v)
+			}
+		}
+	})
+}
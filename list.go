@@ -0,0 +1,77 @@
+package go_memoize
+
+// entryList is an intrusive doubly linked list of cache entries.
+// The zero value is not usable, call init before use. It is not safe for concurrent use.
+type entryList[K comparable, V any] struct {
+	root entry[K, V] // sentinel: root.next is the front and root.prev is the back
+	len  int
+}
+
+// init initializes or clears the list.
+func (l *entryList[K, V]) init() *entryList[K, V] {
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	l.len = 0
+	return l
+}
+
+// front returns the first entry of the list or nil if the list is empty.
+func (l *entryList[K, V]) front() *entry[K, V] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.next
+}
+
+// back returns the last entry of the list or nil if the list is empty.
+func (l *entryList[K, V]) back() *entry[K, V] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// pushFront inserts e at the front of the list.
+func (l *entryList[K, V]) pushFront(e *entry[K, V]) {
+	e.prev = &l.root
+	e.next = l.root.next
+	e.prev.next = e
+	e.next.prev = e
+	e.list = l
+	l.len++
+}
+
+// moveToFront moves e, which must be in the list, to the front of the list.
+func (l *entryList[K, V]) moveToFront(e *entry[K, V]) {
+	if l.root.next == e {
+		return
+	}
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev = &l.root
+	e.next = l.root.next
+	e.prev.next = e
+	e.next.prev = e
+}
+
+// remove removes e, which must be in the list, from the list.
+func (l *entryList[K, V]) remove(e *entry[K, V]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.prev = nil
+	e.next = nil
+	e.list = nil
+	l.len--
+}
+
+// replace puts e in place of old, which must be in the list.
+func (l *entryList[K, V]) replace(old, e *entry[K, V]) {
+	e.prev = old.prev
+	e.next = old.next
+	e.prev.next = e
+	e.next.prev = e
+	e.list = l
+	old.prev = nil
+	old.next = nil
+	old.list = nil
+}
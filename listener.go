@@ -0,0 +1,144 @@
+package go_memoize
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// EventReason is the kind of change reported to the listeners of a cache.
+type EventReason uint8
+
+const (
+	// EventInsert reports a value stored for a key without an entry.
+	EventInsert EventReason = iota
+	// EventUpdate reports a value replacing the entry of a key, including an expired entry the janitor has not removed yet.
+	EventUpdate
+	// EventDelete reports an entry removed explicitly, by Delete, Invalidate or Purge.
+	EventDelete
+	// EventExpire reports an expired entry removed by the janitor.
+	EventExpire
+	// EventEvict reports an entry evicted to stay within the capacity of the cache.
+	EventEvict
+)
+
+// String returns the name of the reason.
+func (r EventReason) String() string {
+	switch r {
+	case EventInsert:
+		return "insert"
+	case EventUpdate:
+		return "update"
+	case EventDelete:
+		return "delete"
+	case EventExpire:
+		return "expire"
+	case EventEvict:
+		return "evict"
+	}
+	return "unknown"
+}
+
+// Event is a change of a cache entry reported to listeners.
+// Value is the value stored by an insert or update, or the value leaving the cache otherwise,
+// and Err is the cached error stored along with it, if any. Old is the value replaced by an update.
+type Event[K comparable, V any] struct {
+	Key    K
+	Value  V
+	Old    V
+	Err    error
+	Reason EventReason
+}
+
+// listeners holds the listeners of a cache, shared by its shards.
+// The slice is replaced rather than modified, so that shards read it without locking.
+type listeners[K comparable, V any] struct {
+	mu  sync.Mutex
+	fns atomic.Pointer[[]func(Event[K, V])]
+}
+
+// add registers a listener.
+func (l *listeners[K, V]) add(fn func(Event[K, V])) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var fns []func(Event[K, V])
+	if old := l.fns.Load(); old != nil {
+		fns = append(fns, *old...)
+	}
+	fns = append(fns, fn)
+	l.fns.Store(&fns)
+}
+
+// active reports whether any listener is registered.
+func (l *listeners[K, V]) active() bool {
+	return l.fns.Load() != nil
+}
+
+// AddListener registers fn to be called on every insert, update, delete, expiry and eviction of an entry.
+//
+// Listeners are called after the change, outside the cache lock, so they may call the cache.
+// Events of keys in the same shard are delivered one at a time in the order of the changes; events of different shards
+// may be delivered concurrently. The goroutine making a change delivers its events before returning, unless events of
+// the shard are being delivered already: the change then returns without waiting, and the goroutine delivering
+// delivers its events too. Expiry events are delivered on a goroutine of the cache rather than by the janitor shared
+// by all caches. A listener should return quickly, as the goroutine delivering the events of its shard keeps delivering
+// the events queued meanwhile, and queued events are held in memory until they are delivered.
+func (c *Cache[K, V]) AddListener(fn func(Event[K, V])) {
+	c.listeners.add(fn)
+}
+
+// emit queues an event of the locked shard for delivery by dispatch, if the cache has listeners.
+func (s *shard[K, V]) emit(reason EventReason, e *entry[K, V], old *entry[K, V]) {
+	if !s.listeners.active() {
+		return
+	}
+	event := Event[K, V]{Key: e.key, Value: e.value, Err: e.err, Reason: reason}
+	if old != nil {
+		event.Old = old.value
+	}
+	s.pending = append(s.pending, event)
+}
+
+// dispatch delivers the queued events of the shard, which must not be locked. Only one goroutine delivers the events
+// of a shard at a time: if another one already is, it delivers the queued events too before returning.
+func (s *shard[K, V]) dispatch() {
+	if !s.listeners.active() {
+		return
+	}
+	s.mu.Lock()
+	if s.dispatching {
+		s.mu.Unlock()
+		return
+	}
+	s.dispatching = true
+	delivered := false
+	defer func() {
+		if !delivered {
+			// a listener panicked, the events it was delivered with are dropped
+			s.mu.Lock()
+			s.dispatching = false
+			s.mu.Unlock()
+		}
+	}()
+	for len(s.pending) > 0 {
+		events := s.pending
+		s.pending = nil
+		s.mu.Unlock()
+		fns := *s.listeners.fns.Load()
+		for _, event := range events {
+			for _, fn := range fns {
+				fn(event)
+			}
+		}
+		s.mu.Lock()
+	}
+	s.dispatching = false
+	s.mu.Unlock()
+	delivered = true
+}
+
+// dispatchAll delivers the queued events of every shard of the cache, see dispatch.
+func (c *cache[K, V]) dispatchAll() {
+	for i := range c.shards {
+		c.shards[i].dispatch()
+	}
+}
@@ -0,0 +1,251 @@
+package go_memoize
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recorder is a listener recording the events it receives.
+type recorder[K comparable, V any] struct {
+	mu     sync.Mutex
+	events []Event[K, V]
+}
+
+func (r *recorder[K, V]) listen(e Event[K, V]) {
+	r.mu.Lock()
+	r.events = append(r.events, e)
+	r.mu.Unlock()
+}
+
+func (r *recorder[K, V]) get() []Event[K, V] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Event[K, V](nil), r.events...)
+}
+
+func TestListenerReportsInsertUpdateAndDelete(t *testing.T) {
+	cache := NewCache[string, int](60)
+	var r recorder[string, int]
+	cache.AddListener(r.listen)
+
+	cache.Set("a", 1)
+	cache.Set("a", 2)
+	cache.Delete("a")
+	cache.Delete("missing")
+	cache.GetOrCompute("b", func() int { return 3 })
+
+	expected := []Event[string, int]{
+		{Key: "a", Value: 1, Reason: EventInsert},
+		{Key: "a", Value: 2, Old: 1, Reason: EventUpdate},
+		{Key: "a", Value: 2, Reason: EventDelete},
+		{Key: "b", Value: 3, Reason: EventInsert},
+	}
+	events := r.get()
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %v", len(expected), events)
+	}
+	for i, e := range expected {
+		if events[i] != e {
+			t.Errorf("Expected %+v, got %+v", e, events[i])
+		}
+	}
+}
+
+func TestListenerReportsCachedErrors(t *testing.T) {
+	errBoom := errors.New("boom")
+	cache := NewCache[string, int](60, WithErrorTTL(time.Minute))
+	var r recorder[string, int]
+	cache.AddListener(r.listen)
+
+	cache.GetOrComputeE("a", func() (int, error) { return 0, errBoom })
+
+	events := r.get()
+	if len(events) != 1 || events[0].Err != errBoom || events[0].Reason != EventInsert {
+		t.Errorf("Expected an insert with the error, got %v", events)
+	}
+}
+
+func TestListenerReportsExpiry(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](1, WithClock(clock))
+	var r recorder[int, int]
+	cache.Set(1, 1)
+	cache.AddListener(r.listen)
+
+	clock.Advance(2 * time.Second)
+	cache.sweeper.sweep()
+
+	waitFor(t, func() bool { return len(r.get()) > 0 })
+	events := r.get()
+	if len(events) != 1 || events[0] != (Event[int, int]{Key: 1, Value: 1, Reason: EventExpire}) {
+		t.Errorf("Expected an expiry of key 1, got %v", events)
+	}
+}
+
+func TestListenerBlockingOnExpiryDoesNotStallOtherCaches(t *testing.T) {
+	clock := NewFakeClock()
+	blocked := NewCache[int, int](1, WithClock(clock))
+	other := NewCache[int, int](1, WithClock(clock))
+	release := make(chan struct{})
+	defer close(release)
+	var delivered atomic.Bool
+	blocked.AddListener(func(e Event[int, int]) {
+		if e.Reason == EventExpire {
+			delivered.Store(true)
+			<-release
+		}
+	})
+	blocked.Set(1, 1)
+	other.Set(1, 1)
+
+	clock.Advance(2 * time.Second)
+	swept := make(chan struct{})
+	go func() {
+		cacheGroupInstance.sweep()
+		close(swept)
+	}()
+	select {
+	case <-swept:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected the janitor not to wait for the listener")
+	}
+	waitFor(t, delivered.Load)
+	if n := cacheLen(other); n != 0 {
+		t.Errorf("Expected 0, got %d", n)
+	}
+
+	// the shared clock keeps ticking while the listener blocks, every millisecond for a cache with a short TTL
+	ticking := NewCacheWithTTL[int, int](16 * time.Millisecond)
+	defer ticking.Close()
+	start := cacheGroupInstance.Now()
+	waitFor(t, func() bool { return cacheGroupInstance.Now() > start })
+}
+
+func TestListenerReportsEviction(t *testing.T) {
+	cache := NewCache[int, int](60, WithCapacity(2), WithEvictionPolicy(LRU))
+	var r recorder[int, int]
+	cache.AddListener(r.listen)
+
+	cache.Set(1, 10)
+	cache.Set(2, 20)
+	cache.Set(3, 30)
+
+	events := r.get()
+	if len(events) != 4 {
+		t.Fatalf("Expected 4 events, got %v", events)
+	}
+	if events[3] != (Event[int, int]{Key: 1, Value: 10, Reason: EventEvict}) {
+		t.Errorf("Expected the eviction of key 1, got %+v", events[3])
+	}
+}
+
+func TestListenerReportsPurge(t *testing.T) {
+	cache := NewCache[int, int](60, WithShards(4))
+	for i := 0; i < 10; i++ {
+		cache.Set(i, i)
+	}
+	var r recorder[int, int]
+	cache.AddListener(r.listen)
+
+	cache.Purge()
+
+	events := r.get()
+	keys := make([]int, 0, len(events))
+	for _, e := range events {
+		if e.Reason != EventDelete {
+			t.Errorf("Expected a delete, got %v", e.Reason)
+		}
+		keys = append(keys, e.Key)
+	}
+	sort.Ints(keys)
+	if len(keys) != 10 || keys[0] != 0 || keys[9] != 9 {
+		t.Errorf("Expected deletes of keys 0 to 9, got %v", keys)
+	}
+}
+
+func TestListenerCanCallTheCache(t *testing.T) {
+	cache := NewCache[int, int](60)
+	var r recorder[int, int]
+	cache.AddListener(func(e Event[int, int]) {
+		r.listen(e)
+		if e.Reason == EventInsert && e.Key < 3 {
+			cache.Set(e.Key+1, e.Value+1)
+		}
+	})
+
+	cache.Set(0, 0)
+
+	events := r.get()
+	if len(events) != 4 {
+		t.Fatalf("Expected 4 events, got %v", events)
+	}
+	for i, e := range events {
+		if e.Key != i {
+			t.Errorf("Expected key %d, got %d", i, e.Key)
+		}
+	}
+}
+
+func TestListenerEventsOfAShardAreOrdered(t *testing.T) {
+	cache := NewCache[int, int](60)
+	var r recorder[int, int]
+	cache.AddListener(r.listen)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				cache.Set(g, i)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	last := make(map[int]int)
+	for _, e := range r.get() {
+		if prev, ok := last[e.Key]; ok && e.Value != prev+1 {
+			t.Fatalf("Expected value %d for key %d, got %d", prev+1, e.Key, e.Value)
+		}
+		last[e.Key] = e.Value
+	}
+	if len(last) != 8 {
+		t.Errorf("Expected 8, got %d", len(last))
+	}
+}
+
+func TestListenerPanicDoesNotStopDelivery(t *testing.T) {
+	cache := NewCache[int, int](60)
+	var r recorder[int, int]
+	cache.AddListener(func(e Event[int, int]) {
+		if e.Key == 0 {
+			panic("boom")
+		}
+		r.listen(e)
+	})
+
+	func() {
+		defer func() { _ = recover() }()
+		cache.Set(0, 0)
+	}()
+	cache.Set(1, 1)
+
+	if events := r.get(); len(events) != 1 || events[0].Key != 1 {
+		t.Errorf("Expected the insert of key 1, got %v", events)
+	}
+}
+
+func TestEventReasonString(t *testing.T) {
+	for reason, expected := range map[EventReason]string{
+		EventInsert: "insert", EventUpdate: "update", EventDelete: "delete", EventExpire: "expire", EventEvict: "evict",
+	} {
+		if reason.String() != expected {
+			t.Errorf("Expected %s, got %s", expected, reason)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package go_memoize
+
+import (
+	"context"
+	"time"
+)
+
+// MemoizeE returns a memoized version of the error-returning compute function with a specified TTL.
+// V is the type of the value returned by the compute function.
+// Results are cached only when the compute function returns a nil error.
+func MemoizeE[V any](computeFn func() (V, error), ttl time.Duration, opts ...Option) func() (V, error) {
+	cache := newCache[uint64, V](1, ttl, nil, opts...)
+	return func() (V, error) {
+		if e := cache.lookup(0); e != nil {
+			return e.value, e.err
+		}
+		return cache.GetOrComputeE(0, func() (V, error) {
+			return computeFn()
+		})
+	}
+}
+
+// MemoizeE1 returns a memoized version of the error-returning compute function with a single key and a specified TTL.
+// K is the type of the key, and V is the type of the value returned by the compute function.
+func MemoizeE1[K comparable, V any](computeFn func(K) (V, error), ttl time.Duration, opts ...Option) func(K) (V, error) {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return func(k K) (V, error) {
+		if e := cache.lookup(k); e != nil {
+			return e.value, e.err
+		}
+		return cache.GetOrComputeE(k, func() (V, error) {
+			return computeFn(k)
+		})
+	}
+}
+
+// MemoizeE2 returns a memoized version of the error-returning compute function with two keys and a specified TTL.
+// K1 and K2 are the types of the keys, and V is the type of the value returned by the compute function.
+func MemoizeE2[K1, K2 comparable, V any](computeFn func(K1, K2) (V, error), ttl time.Duration, opts ...Option) func(K1, K2) (V, error) {
+	cache := newCache[args2[K1, K2], V](0, ttl, hashOf[args2[K1, K2]], opts...)
+	hasher := cache.keyHasher()
+	return func(key1 K1, key2 K2) (V, error) {
+		key := newArgs2(hasher, key1, key2)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.GetOrComputeE(key, func() (V, error) {
+			return computeFn(key1, key2)
+		})
+	}
+}
+
+// MemoizeE3 returns a memoized version of the error-returning compute function with three keys and a specified TTL.
+// K1, K2, and K3 are the types of the keys, and V is the type of the value returned by the compute function.
+func MemoizeE3[K1, K2, K3 comparable, V any](computeFn func(K1, K2, K3) (V, error), ttl time.Duration, opts ...Option) func(K1, K2, K3) (V, error) {
+	cache := newCache[args3[K1, K2, K3], V](0, ttl, hashOf[args3[K1, K2, K3]], opts...)
+	hasher := cache.keyHasher()
+	return func(key1 K1, key2 K2, key3 K3) (V, error) {
+		key := newArgs3(hasher, key1, key2, key3)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.GetOrComputeE(key, func() (V, error) {
+			return computeFn(key1, key2, key3)
+		})
+	}
+}
+
+// MemoizeE4 returns a memoized version of the error-returning compute function with four keys and a specified TTL.
+// K1, K2, K3, and K4 are the types of the keys, and V is the type of the value returned by the compute function.
+func MemoizeE4[K1, K2, K3, K4 comparable, V any](computeFn func(K1, K2, K3, K4) (V, error), ttl time.Duration, opts ...Option) func(K1, K2, K3, K4) (V, error) {
+	cache := newCache[args4[K1, K2, K3, K4], V](0, ttl, hashOf[args4[K1, K2, K3, K4]], opts...)
+	hasher := cache.keyHasher()
+	return func(key1 K1, key2 K2, key3 K3, key4 K4) (V, error) {
+		key := newArgs4(hasher, key1, key2, key3, key4)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.GetOrComputeE(key, func() (V, error) {
+			return computeFn(key1, key2, key3, key4)
+		})
+	}
+}
+
+// MemoizeE5 returns a memoized version of the error-returning compute function with five keys and a specified TTL.
+// K1, K2, K3, K4, and K5 are the types of the keys, and V is the type of the value returned by the compute function.
+func MemoizeE5[K1, K2, K3, K4, K5 comparable, V any](computeFn func(K1, K2, K3, K4, K5) (V, error), ttl time.Duration, opts ...Option) func(K1, K2, K3, K4, K5) (V, error) {
+	cache := newCache[args5[K1, K2, K3, K4, K5], V](0, ttl, hashOf[args5[K1, K2, K3, K4, K5]], opts...)
+	hasher := cache.keyHasher()
+	return func(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5) (V, error) {
+		key := newArgs5(hasher, key1, key2, key3, key4, key5)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.GetOrComputeE(key, func() (V, error) {
+			return computeFn(key1, key2, key3, key4, key5)
+		})
+	}
+}
+
+// MemoizeE6 returns a memoized version of the error-returning compute function with six keys and a specified TTL.
+// K1, K2, K3, K4, K5, and K6 are the types of the keys, and V is the type of the value returned by the compute function.
+func MemoizeE6[K1, K2, K3, K4, K5, K6 comparable, V any](computeFn func(K1, K2, K3, K4, K5, K6) (V, error), ttl time.Duration, opts ...Option) func(K1, K2, K3, K4, K5, K6) (V, error) {
+	cache := newCache[args6[K1, K2, K3, K4, K5, K6], V](0, ttl, hashOf[args6[K1, K2, K3, K4, K5, K6]], opts...)
+	hasher := cache.keyHasher()
+	return func(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6) (V, error) {
+		key := newArgs6(hasher, key1, key2, key3, key4, key5, key6)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.GetOrComputeE(key, func() (V, error) {
+			return computeFn(key1, key2, key3, key4, key5, key6)
+		})
+	}
+}
+
+// MemoizeE7 returns a memoized version of the error-returning compute function with seven keys and a specified TTL.
+// K1, K2, K3, K4, K5, K6, and K7 are the types of the keys, and V is the type of the value returned by the compute function.
+func MemoizeE7[K1, K2, K3, K4, K5, K6, K7 comparable, V any](computeFn func(K1, K2, K3, K4, K5, K6, K7) (V, error), ttl time.Duration, opts ...Option) func(K1, K2, K3, K4, K5, K6, K7) (V, error) {
+	cache := newCache[args7[K1, K2, K3, K4, K5, K6, K7], V](0, ttl, hashOf[args7[K1, K2, K3, K4, K5, K6, K7]], opts...)
+	hasher := cache.keyHasher()
+	return func(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, key7 K7) (V, error) {
+		key := newArgs7(hasher, key1, key2, key3, key4, key5, key6, key7)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.GetOrComputeE(key, func() (V, error) {
+			return computeFn(key1, key2, key3, key4, key5, key6, key7)
+		})
+	}
+}
+
+// MemoizeCtxE returns a memoized version of the error-returning compute function with context and a specified TTL.
+// Results are cached only when the compute function returns a nil error.
+func MemoizeCtxE[V any](computeFn func(context.Context) (V, error), ttl time.Duration, opts ...Option) func(context.Context) (V, error) {
+	cache := newCache[uint64, V](1, ttl, nil, opts...)
+	return func(ctx context.Context) (V, error) {
+		if e := cache.lookup(0); e != nil {
+			return e.value, e.err
+		}
+		return cache.getOrComputeCtx(ctx, 0, func() (V, error) {
+			return computeFn(ctx)
+		}, func() (V, error) {
+			return computeFn(context.WithoutCancel(ctx))
+		})
+	}
+}
+
+// MemoizeCtxE1 returns a memoized version of the error-returning compute function with context, a single key and a specified TTL.
+func MemoizeCtxE1[K comparable, V any](computeFn func(context.Context, K) (V, error), ttl time.Duration, opts ...Option) func(context.Context, K) (V, error) {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return func(ctx context.Context, k K) (V, error) {
+		if e := cache.lookup(k); e != nil {
+			return e.value, e.err
+		}
+		return cache.getOrComputeCtx(ctx, k, func() (V, error) {
+			return computeFn(ctx, k)
+		}, func() (V, error) {
+			return computeFn(context.WithoutCancel(ctx), k)
+		})
+	}
+}
+
+// MemoizeCtxE2 returns a memoized version of the error-returning compute function with context, two keys and a specified TTL.
+func MemoizeCtxE2[K1, K2 comparable, V any](computeFn func(context.Context, K1, K2) (V, error), ttl time.Duration, opts ...Option) func(context.Context, K1, K2) (V, error) {
+	cache := newCache[args2[K1, K2], V](0, ttl, hashOf[args2[K1, K2]], opts...)
+	hasher := cache.keyHasher()
+	return func(ctx context.Context, key1 K1, key2 K2) (V, error) {
+		key := newArgs2(hasher, key1, key2)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.getOrComputeCtx(ctx, key, func() (V, error) {
+			return computeFn(ctx, key1, key2)
+		}, func() (V, error) {
+			return computeFn(context.WithoutCancel(ctx), key1, key2)
+		})
+	}
+}
+
+// MemoizeCtxE3 returns a memoized version of the error-returning compute function with context, three keys and a specified TTL.
+func MemoizeCtxE3[K1, K2, K3 comparable, V any](computeFn func(context.Context, K1, K2, K3) (V, error), ttl time.Duration, opts ...Option) func(context.Context, K1, K2, K3) (V, error) {
+	cache := newCache[args3[K1, K2, K3], V](0, ttl, hashOf[args3[K1, K2, K3]], opts...)
+	hasher := cache.keyHasher()
+	return func(ctx context.Context, key1 K1, key2 K2, key3 K3) (V, error) {
+		key := newArgs3(hasher, key1, key2, key3)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.getOrComputeCtx(ctx, key, func() (V, error) {
+			return computeFn(ctx, key1, key2, key3)
+		}, func() (V, error) {
+			return computeFn(context.WithoutCancel(ctx), key1, key2, key3)
+		})
+	}
+}
+
+// MemoizeCtxE4 returns a memoized version of the error-returning compute function with context, four keys and a specified TTL.
+func MemoizeCtxE4[K1, K2, K3, K4 comparable, V any](computeFn func(context.Context, K1, K2, K3, K4) (V, error), ttl time.Duration, opts ...Option) func(context.Context, K1, K2, K3, K4) (V, error) {
+	cache := newCache[args4[K1, K2, K3, K4], V](0, ttl, hashOf[args4[K1, K2, K3, K4]], opts...)
+	hasher := cache.keyHasher()
+	return func(ctx context.Context, key1 K1, key2 K2, key3 K3, key4 K4) (V, error) {
+		key := newArgs4(hasher, key1, key2, key3, key4)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.getOrComputeCtx(ctx, key, func() (V, error) {
+			return computeFn(ctx, key1, key2, key3, key4)
+		}, func() (V, error) {
+			return computeFn(context.WithoutCancel(ctx), key1, key2, key3, key4)
+		})
+	}
+}
+
+// MemoizeCtxE5 returns a memoized version of the error-returning compute function with context, five keys and a specified TTL.
+func MemoizeCtxE5[K1, K2, K3, K4, K5 comparable, V any](computeFn func(context.Context, K1, K2, K3, K4, K5) (V, error), ttl time.Duration, opts ...Option) func(context.Context, K1, K2, K3, K4, K5) (V, error) {
+	cache := newCache[args5[K1, K2, K3, K4, K5], V](0, ttl, hashOf[args5[K1, K2, K3, K4, K5]], opts...)
+	hasher := cache.keyHasher()
+	return func(ctx context.Context, key1 K1, key2 K2, key3 K3, key4 K4, key5 K5) (V, error) {
+		key := newArgs5(hasher, key1, key2, key3, key4, key5)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.getOrComputeCtx(ctx, key, func() (V, error) {
+			return computeFn(ctx, key1, key2, key3, key4, key5)
+		}, func() (V, error) {
+			return computeFn(context.WithoutCancel(ctx), key1, key2, key3, key4, key5)
+		})
+	}
+}
+
+// MemoizeCtxE6 returns a memoized version of the error-returning compute function with context, six keys and a specified TTL.
+func MemoizeCtxE6[K1, K2, K3, K4, K5, K6 comparable, V any](computeFn func(context.Context, K1, K2, K3, K4, K5, K6) (V, error), ttl time.Duration, opts ...Option) func(context.Context, K1, K2, K3, K4, K5, K6) (V, error) {
+	cache := newCache[args6[K1, K2, K3, K4, K5, K6], V](0, ttl, hashOf[args6[K1, K2, K3, K4, K5, K6]], opts...)
+	hasher := cache.keyHasher()
+	return func(ctx context.Context, key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6) (V, error) {
+		key := newArgs6(hasher, key1, key2, key3, key4, key5, key6)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.getOrComputeCtx(ctx, key, func() (V, error) {
+			return computeFn(ctx, key1, key2, key3, key4, key5, key6)
+		}, func() (V, error) {
+			return computeFn(context.WithoutCancel(ctx), key1, key2, key3, key4, key5, key6)
+		})
+	}
+}
+
+// MemoizeCtxE7 returns a memoized version of the error-returning compute function with context, seven keys and a specified TTL.
+func MemoizeCtxE7[K1, K2, K3, K4, K5, K6, K7 comparable, V any](computeFn func(context.Context, K1, K2, K3, K4, K5, K6, K7) (V, error), ttl time.Duration, opts ...Option) func(context.Context, K1, K2, K3, K4, K5, K6, K7) (V, error) {
+	cache := newCache[args7[K1, K2, K3, K4, K5, K6, K7], V](0, ttl, hashOf[args7[K1, K2, K3, K4, K5, K6, K7]], opts...)
+	hasher := cache.keyHasher()
+	return func(ctx context.Context, key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, key7 K7) (V, error) {
+		key := newArgs7(hasher, key1, key2, key3, key4, key5, key6, key7)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.getOrComputeCtx(ctx, key, func() (V, error) {
+			return computeFn(ctx, key1, key2, key3, key4, key5, key6, key7)
+		}, func() (V, error) {
+			return computeFn(context.WithoutCancel(ctx), key1, key2, key3, key4, key5, key6, key7)
+		})
+	}
+}
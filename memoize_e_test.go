@@ -0,0 +1,297 @@
+package go_memoize
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errCompute = errors.New("compute failed")
+
+func TestMemoizeE_CachesSuccess(t *testing.T) {
+	count := 0
+	computeFn := func() (int, error) {
+		count++
+		return 1, nil
+	}
+	memoizedFn := MemoizeE(computeFn, 0)
+	memoizedFn()
+	v, err := memoizedFn()
+	if err != nil {
+		t.Errorf("Expected nil error, got %v", err)
+	}
+	if v != 1 {
+		t.Errorf("Expected 1, got %d", v)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+}
+
+func TestMemoizeE_DoesNotCacheErrors(t *testing.T) {
+	count := 0
+	computeFn := func() (int, error) {
+		count++
+		if count == 1 {
+			return 0, errCompute
+		}
+		return 1, nil
+	}
+	memoizedFn := MemoizeE(computeFn, 0)
+	if _, err := memoizedFn(); !errors.Is(err, errCompute) {
+		t.Errorf("Expected %v, got %v", errCompute, err)
+	}
+	v, err := memoizedFn()
+	if err != nil || v != 1 {
+		t.Errorf("Expected 1 and nil error, got %d and %v", v, err)
+	}
+	memoizedFn()
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+}
+
+func TestMemoizeE1_DoesNotCacheErrors(t *testing.T) {
+	count := 0
+	computeFn := func(key int) (int, error) {
+		count++
+		if key < 0 {
+			return 0, errCompute
+		}
+		return key * 2, nil
+	}
+	memoizedFn := MemoizeE1(computeFn, 0)
+	memoizedFn(-1)
+	memoizedFn(-1)
+	memoizedFn(21)
+	memoizedFn(21)
+	if count != 3 {
+		t.Errorf("Expected 3, got %d", count)
+	}
+}
+
+func TestMemoizeE7_NoExpiry(t *testing.T) {
+	count := 0
+	computeFn := func(key1, key2, key3, key4, key5, key6, key7 int) (int, error) {
+		count++
+		return key1 + key2 + key3 + key4 + key5 + key6 + key7, nil
+	}
+	memoizedFn := MemoizeE7(computeFn, 0)
+	memoizedFn(1, 2, 3, 4, 5, 6, 7)
+	v, _ := memoizedFn(1, 2, 3, 4, 5, 6, 7)
+	if v != 28 {
+		t.Errorf("Expected 28, got %d", v)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+}
+
+func TestMemoizeE1WithTTL(t *testing.T) {
+	clock := NewFakeClock()
+	count := 0
+	computeFn := func(key int) (int, error) {
+		count++
+		return key * 2, nil
+	}
+	memoizedFn := MemoizeE1(computeFn, 1*time.Second, WithClock(clock))
+	memoizedFn(21)
+	memoizedFn(21)
+	if count != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+
+	clock.Advance(2 * time.Second)
+	memoizedFn(21)
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+}
+
+func TestMemoizeE1_ConcurrentErrorIsShared(t *testing.T) {
+	var count int32
+	release := make(chan struct{})
+	computeFn := func(key int) (int, error) {
+		atomic.AddInt32(&count, 1)
+		<-release
+		return 0, errCompute
+	}
+	memoizedFn := MemoizeE1(computeFn, 1*time.Minute)
+
+	const callers = 10
+	errs := make(chan error, callers)
+	var started, wg sync.WaitGroup
+	started.Add(callers)
+	wg.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer wg.Done()
+			started.Done()
+			_, err := memoizedFn(21)
+			errs <- err
+		}()
+	}
+	started.Wait()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if !errors.Is(err, errCompute) {
+			t.Errorf("Expected %v, got %v", errCompute, err)
+		}
+	}
+	if atomic.LoadInt32(&count) != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+}
+
+func TestMemoizeCtxE1_DoesNotCacheErrors(t *testing.T) {
+	count := 0
+	computeFn := func(ctx context.Context, key int) (int, error) {
+		count++
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		return key * 2, nil
+	}
+	memoizedFn := MemoizeCtxE1(computeFn, 0)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := memoizedFn(canceled, 21); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+	v, err := memoizedFn(context.Background(), 21)
+	if err != nil || v != 42 {
+		t.Errorf("Expected 42 and nil error, got %d and %v", v, err)
+	}
+	memoizedFn(context.Background(), 21)
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+}
+
+func TestMemoizeCtxE7_ConcurrentAccess(t *testing.T) {
+	var count int32
+	computeFn := func(ctx context.Context, key1, key2, key3, key4, key5, key6, key7 int) (int, error) {
+		atomic.AddInt32(&count, 1)
+		time.Sleep(10 * time.Millisecond)
+		return key1 + key2 + key3 + key4 + key5 + key6 + key7, nil
+	}
+	memoizedFn := MemoizeCtxE7(computeFn, 1*time.Minute)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			memoizedFn(context.Background(), 1, 2, 3, 4, 5, 6, 7)
+		}()
+	}
+	wg.Wait()
+	if atomic.LoadInt32(&count) != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+}
+
+func TestMemoizeE1WithErrorTTL(t *testing.T) {
+	clock := NewFakeClock()
+	count := 0
+	computeFn := func(key int) (int, error) {
+		count++
+		return 0, errCompute
+	}
+	memoizedFn := MemoizeE1(computeFn, 0, WithErrorTTL(1*time.Second), WithClock(clock))
+	memoizedFn(21)
+	if _, err := memoizedFn(21); !errors.Is(err, errCompute) {
+		t.Errorf("Expected %v, got %v", errCompute, err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+
+	clock.Advance(2 * time.Second)
+	memoizedFn(21)
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+}
+
+func TestMemoizeE1WithErrorFilter(t *testing.T) {
+	errNotFound := errors.New("not found")
+	count := 0
+	computeFn := func(key int) (int, error) {
+		count++
+		if key == 404 {
+			return 0, errNotFound
+		}
+		return 0, errCompute
+	}
+	memoizedFn := MemoizeE1(computeFn, 0,
+		WithErrorTTL(1*time.Minute),
+		WithErrorFilter(func(err error) bool { return errors.Is(err, errNotFound) }),
+	)
+	memoizedFn(404)
+	memoizedFn(404)
+	if count != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+	memoizedFn(500)
+	memoizedFn(500)
+	if count != 3 {
+		t.Errorf("Expected 3, got %d", count)
+	}
+}
+
+func TestMemoizeE1WithSubSecondErrorTTL(t *testing.T) {
+	count := 0
+	computeFn := func(key int) (int, error) {
+		count++
+		return 0, errCompute
+	}
+	memoizedFn := MemoizeE1(computeFn, time.Minute, WithErrorTTL(100*time.Millisecond))
+	memoizedFn(21)
+	memoizedFn(21)
+	if count != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	memoizedFn(21)
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+}
+
+func TestMemoizeCtxE1WithErrorTTL_DoesNotCacheCancellation(t *testing.T) {
+	count := 0
+	memoizedFn := MemoizeCtxE1(func(ctx context.Context, k int) (int, error) {
+		count++
+		if err := ctx.Err(); err != nil {
+			return 0, fmt.Errorf("loading %d: %w", k, err)
+		}
+		return k * 2, nil
+	}, time.Minute, WithErrorTTL(time.Minute), WithErrorFilter(func(error) bool { return true }))
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := memoizedFn(canceled, 21); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected %v, got %v", context.Canceled, err)
+	}
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	if _, err := memoizedFn(expired, 21); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if v, err := memoizedFn(context.Background(), 21); v != 42 || err != nil {
+		t.Errorf("Expected 42, got %d and %v", v, err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3, got %d", count)
+	}
+}
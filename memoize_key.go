@@ -0,0 +1,248 @@
+package go_memoize
+
+import (
+	"context"
+	"time"
+)
+
+// The MemoizeKey functions memoize compute functions whose arguments are not comparable, such as slices, maps
+// or structs holding them. The cache is keyed by the result of keyFn, which must return equal keys for arguments
+// that compute the same result; the arguments themselves are passed through to the compute function.
+
+// MemoizeKey1 returns a memoized version of the compute function with a single argument and a specified TTL,
+// caching results by the key keyFn derives from the arguments.
+// A is the type of the argument, K is the type of the key, and V is the type of the value returned by the compute function.
+func MemoizeKey1[A any, K comparable, V any](computeFn func(A) V, keyFn func(A) K, ttl time.Duration, opts ...Option) func(A) V {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return func(a A) V {
+		key := keyFn(a)
+		if e := cache.lookup(key); e != nil {
+			return e.value
+		}
+		return cache.GetOrCompute(key, func() V {
+			return computeFn(a)
+		})
+	}
+}
+
+// MemoizeKey2 returns a memoized version of the compute function with two arguments and a specified TTL,
+// caching results by the key keyFn derives from the arguments.
+// A1 and A2 are the types of the arguments, K is the type of the key, and V is the type of the value returned by the compute function.
+func MemoizeKey2[A1, A2 any, K comparable, V any](computeFn func(A1, A2) V, keyFn func(A1, A2) K, ttl time.Duration, opts ...Option) func(A1, A2) V {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return func(a1 A1, a2 A2) V {
+		key := keyFn(a1, a2)
+		if e := cache.lookup(key); e != nil {
+			return e.value
+		}
+		return cache.GetOrCompute(key, func() V {
+			return computeFn(a1, a2)
+		})
+	}
+}
+
+// MemoizeKey3 returns a memoized version of the compute function with three arguments and a specified TTL,
+// caching results by the key keyFn derives from the arguments.
+// A1, A2, and A3 are the types of the arguments, K is the type of the key, and V is the type of the value returned by the compute function.
+func MemoizeKey3[A1, A2, A3 any, K comparable, V any](computeFn func(A1, A2, A3) V, keyFn func(A1, A2, A3) K, ttl time.Duration, opts ...Option) func(A1, A2, A3) V {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return func(a1 A1, a2 A2, a3 A3) V {
+		key := keyFn(a1, a2, a3)
+		if e := cache.lookup(key); e != nil {
+			return e.value
+		}
+		return cache.GetOrCompute(key, func() V {
+			return computeFn(a1, a2, a3)
+		})
+	}
+}
+
+// MemoizeKey4 returns a memoized version of the compute function with four arguments and a specified TTL,
+// caching results by the key keyFn derives from the arguments.
+// A1, A2, A3, and A4 are the types of the arguments, K is the type of the key, and V is the type of the value returned by the compute function.
+func MemoizeKey4[A1, A2, A3, A4 any, K comparable, V any](computeFn func(A1, A2, A3, A4) V, keyFn func(A1, A2, A3, A4) K, ttl time.Duration, opts ...Option) func(A1, A2, A3, A4) V {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return func(a1 A1, a2 A2, a3 A3, a4 A4) V {
+		key := keyFn(a1, a2, a3, a4)
+		if e := cache.lookup(key); e != nil {
+			return e.value
+		}
+		return cache.GetOrCompute(key, func() V {
+			return computeFn(a1, a2, a3, a4)
+		})
+	}
+}
+
+// MemoizeKey5 returns a memoized version of the compute function with five arguments and a specified TTL,
+// caching results by the key keyFn derives from the arguments.
+// A1, A2, A3, A4, and A5 are the types of the arguments, K is the type of the key, and V is the type of the value returned by the compute function.
+func MemoizeKey5[A1, A2, A3, A4, A5 any, K comparable, V any](computeFn func(A1, A2, A3, A4, A5) V, keyFn func(A1, A2, A3, A4, A5) K, ttl time.Duration, opts ...Option) func(A1, A2, A3, A4, A5) V {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return func(a1 A1, a2 A2, a3 A3, a4 A4, a5 A5) V {
+		key := keyFn(a1, a2, a3, a4, a5)
+		if e := cache.lookup(key); e != nil {
+			return e.value
+		}
+		return cache.GetOrCompute(key, func() V {
+			return computeFn(a1, a2, a3, a4, a5)
+		})
+	}
+}
+
+// MemoizeKey6 returns a memoized version of the compute function with six arguments and a specified TTL,
+// caching results by the key keyFn derives from the arguments.
+// A1, A2, A3, A4, A5, and A6 are the types of the arguments, K is the type of the key, and V is the type of the value returned by the compute function.
+func MemoizeKey6[A1, A2, A3, A4, A5, A6 any, K comparable, V any](computeFn func(A1, A2, A3, A4, A5, A6) V, keyFn func(A1, A2, A3, A4, A5, A6) K, ttl time.Duration, opts ...Option) func(A1, A2, A3, A4, A5, A6) V {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return func(a1 A1, a2 A2, a3 A3, a4 A4, a5 A5, a6 A6) V {
+		key := keyFn(a1, a2, a3, a4, a5, a6)
+		if e := cache.lookup(key); e != nil {
+			return e.value
+		}
+		return cache.GetOrCompute(key, func() V {
+			return computeFn(a1, a2, a3, a4, a5, a6)
+		})
+	}
+}
+
+// MemoizeKey7 returns a memoized version of the compute function with seven arguments and a specified TTL,
+// caching results by the key keyFn derives from the arguments.
+// A1, A2, A3, A4, A5, A6, and A7 are the types of the arguments, K is the type of the key, and V is the type of the value returned by the compute function.
+func MemoizeKey7[A1, A2, A3, A4, A5, A6, A7 any, K comparable, V any](computeFn func(A1, A2, A3, A4, A5, A6, A7) V, keyFn func(A1, A2, A3, A4, A5, A6, A7) K, ttl time.Duration, opts ...Option) func(A1, A2, A3, A4, A5, A6, A7) V {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return func(a1 A1, a2 A2, a3 A3, a4 A4, a5 A5, a6 A6, a7 A7) V {
+		key := keyFn(a1, a2, a3, a4, a5, a6, a7)
+		if e := cache.lookup(key); e != nil {
+			return e.value
+		}
+		return cache.GetOrCompute(key, func() V {
+			return computeFn(a1, a2, a3, a4, a5, a6, a7)
+		})
+	}
+}
+
+// MemoizeCtxKey1 returns a memoized version of the compute function with a single argument and a specified TTL,
+// caching results by the key keyFn derives from the arguments. The context is not part of the key.
+func MemoizeCtxKey1[A any, K comparable, V any](computeFn func(context.Context, A) V, keyFn func(A) K, ttl time.Duration, opts ...Option) func(context.Context, A) V {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return func(ctx context.Context, a A) V {
+		key := keyFn(a)
+		if e := cache.lookup(key); e != nil {
+			return e.value
+		}
+		value, _ := cache.getOrCompute(key, func() (V, error) {
+			return computeFn(ctx, a), nil
+		}, func() (V, error) {
+			return computeFn(context.WithoutCancel(ctx), a), nil
+		})
+		return value
+	}
+}
+
+// MemoizeCtxKey2 returns a memoized version of the compute function with two arguments and a specified TTL,
+// caching results by the key keyFn derives from the arguments. The context is not part of the key.
+func MemoizeCtxKey2[A1, A2 any, K comparable, V any](computeFn func(context.Context, A1, A2) V, keyFn func(A1, A2) K, ttl time.Duration, opts ...Option) func(context.Context, A1, A2) V {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return func(ctx context.Context, a1 A1, a2 A2) V {
+		key := keyFn(a1, a2)
+		if e := cache.lookup(key); e != nil {
+			return e.value
+		}
+		value, _ := cache.getOrCompute(key, func() (V, error) {
+			return computeFn(ctx, a1, a2), nil
+		}, func() (V, error) {
+			return computeFn(context.WithoutCancel(ctx), a1, a2), nil
+		})
+		return value
+	}
+}
+
+// MemoizeCtxKey3 returns a memoized version of the compute function with three arguments and a specified TTL,
+// caching results by the key keyFn derives from the arguments. The context is not part of the key.
+func MemoizeCtxKey3[A1, A2, A3 any, K comparable, V any](computeFn func(context.Context, A1, A2, A3) V, keyFn func(A1, A2, A3) K, ttl time.Duration, opts ...Option) func(context.Context, A1, A2, A3) V {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return func(ctx context.Context, a1 A1, a2 A2, a3 A3) V {
+		key := keyFn(a1, a2, a3)
+		if e := cache.lookup(key); e != nil {
+			return e.value
+		}
+		value, _ := cache.getOrCompute(key, func() (V, error) {
+			return computeFn(ctx, a1, a2, a3), nil
+		}, func() (V, error) {
+			return computeFn(context.WithoutCancel(ctx), a1, a2, a3), nil
+		})
+		return value
+	}
+}
+
+// MemoizeCtxKey4 returns a memoized version of the compute function with four arguments and a specified TTL,
+// caching results by the key keyFn derives from the arguments. The context is not part of the key.
+func MemoizeCtxKey4[A1, A2, A3, A4 any, K comparable, V any](computeFn func(context.Context, A1, A2, A3, A4) V, keyFn func(A1, A2, A3, A4) K, ttl time.Duration, opts ...Option) func(context.Context, A1, A2, A3, A4) V {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return func(ctx context.Context, a1 A1, a2 A2, a3 A3, a4 A4) V {
+		key := keyFn(a1, a2, a3, a4)
+		if e := cache.lookup(key); e != nil {
+			return e.value
+		}
+		value, _ := cache.getOrCompute(key, func() (V, error) {
+			return computeFn(ctx, a1, a2, a3, a4), nil
+		}, func() (V, error) {
+			return computeFn(context.WithoutCancel(ctx), a1, a2, a3, a4), nil
+		})
+		return value
+	}
+}
+
+// MemoizeCtxKey5 returns a memoized version of the compute function with five arguments and a specified TTL,
+// caching results by the key keyFn derives from the arguments. The context is not part of the key.
+func MemoizeCtxKey5[A1, A2, A3, A4, A5 any, K comparable, V any](computeFn func(context.Context, A1, A2, A3, A4, A5) V, keyFn func(A1, A2, A3, A4, A5) K, ttl time.Duration, opts ...Option) func(context.Context, A1, A2, A3, A4, A5) V {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return func(ctx context.Context, a1 A1, a2 A2, a3 A3, a4 A4, a5 A5) V {
+		key := keyFn(a1, a2, a3, a4, a5)
+		if e := cache.lookup(key); e != nil {
+			return e.value
+		}
+		value, _ := cache.getOrCompute(key, func() (V, error) {
+			return computeFn(ctx, a1, a2, a3, a4, a5), nil
+		}, func() (V, error) {
+			return computeFn(context.WithoutCancel(ctx), a1, a2, a3, a4, a5), nil
+		})
+		return value
+	}
+}
+
+// MemoizeCtxKey6 returns a memoized version of the compute function with six arguments and a specified TTL,
+// caching results by the key keyFn derives from the arguments. The context is not part of the key.
+func MemoizeCtxKey6[A1, A2, A3, A4, A5, A6 any, K comparable, V any](computeFn func(context.Context, A1, A2, A3, A4, A5, A6) V, keyFn func(A1, A2, A3, A4, A5, A6) K, ttl time.Duration, opts ...Option) func(context.Context, A1, A2, A3, A4, A5, A6) V {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return func(ctx context.Context, a1 A1, a2 A2, a3 A3, a4 A4, a5 A5, a6 A6) V {
+		key := keyFn(a1, a2, a3, a4, a5, a6)
+		if e := cache.lookup(key); e != nil {
+			return e.value
+		}
+		value, _ := cache.getOrCompute(key, func() (V, error) {
+			return computeFn(ctx, a1, a2, a3, a4, a5, a6), nil
+		}, func() (V, error) {
+			return computeFn(context.WithoutCancel(ctx), a1, a2, a3, a4, a5, a6), nil
+		})
+		return value
+	}
+}
+
+// MemoizeCtxKey7 returns a memoized version of the compute function with seven arguments and a specified TTL,
+// caching results by the key keyFn derives from the arguments. The context is not part of the key.
+func MemoizeCtxKey7[A1, A2, A3, A4, A5, A6, A7 any, K comparable, V any](computeFn func(context.Context, A1, A2, A3, A4, A5, A6, A7) V, keyFn func(A1, A2, A3, A4, A5, A6, A7) K, ttl time.Duration, opts ...Option) func(context.Context, A1, A2, A3, A4, A5, A6, A7) V {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return func(ctx context.Context, a1 A1, a2 A2, a3 A3, a4 A4, a5 A5, a6 A6, a7 A7) V {
+		key := keyFn(a1, a2, a3, a4, a5, a6, a7)
+		if e := cache.lookup(key); e != nil {
+			return e.value
+		}
+		value, _ := cache.getOrCompute(key, func() (V, error) {
+			return computeFn(ctx, a1, a2, a3, a4, a5, a6, a7), nil
+		}, func() (V, error) {
+			return computeFn(context.WithoutCancel(ctx), a1, a2, a3, a4, a5, a6, a7), nil
+		})
+		return value
+	}
+}
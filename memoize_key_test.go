@@ -0,0 +1,176 @@
+package go_memoize
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMemoizeKey1_Slice(t *testing.T) {
+	count := 0
+	computeFn := func(filters []string) int {
+		count++
+		return len(filters)
+	}
+	memoizedFn := MemoizeKey1(computeFn, func(filters []string) string {
+		return strings.Join(filters, "\x00")
+	}, 0)
+	if v := memoizedFn([]string{"a", "b"}); v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+	if v := memoizedFn([]string{"a", "b"}); v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+	if v := memoizedFn([]string{"a", "b", "c"}); v != 3 {
+		t.Errorf("Expected 3, got %d", v)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+}
+
+func TestMemoizeKey1_Map(t *testing.T) {
+	count := 0
+	computeFn := func(m map[string]int) int {
+		count++
+		sum := 0
+		for _, v := range m {
+			sum += v
+		}
+		return sum
+	}
+	keyFn := func(m map[string]int) string {
+		keys := make([]string, 0, len(m))
+		for k, v := range m {
+			keys = append(keys, fmt.Sprintf("%s=%d", k, v))
+		}
+		sort.Strings(keys)
+		return strings.Join(keys, ",")
+	}
+	memoizedFn := MemoizeKey1(computeFn, keyFn, 0)
+	memoizedFn(map[string]int{"a": 1, "b": 2})
+	if v := memoizedFn(map[string]int{"b": 2, "a": 1}); v != 3 {
+		t.Errorf("Expected 3, got %d", v)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+}
+
+func TestMemoizeKey1_PassesRawArguments(t *testing.T) {
+	type request struct {
+		ID    int
+		Items []string
+	}
+	// The key ignores Items, so a second request with the same ID reuses the first result.
+	memoizedFn := MemoizeKey1(func(r request) string {
+		return strings.Join(r.Items, "+")
+	}, func(r request) int {
+		return r.ID
+	}, 0)
+	if v := memoizedFn(request{1, []string{"x", "y"}}); v != "x+y" {
+		t.Errorf("Expected x+y, got %s", v)
+	}
+	if v := memoizedFn(request{1, []string{"z"}}); v != "x+y" {
+		t.Errorf("Expected x+y, got %s", v)
+	}
+}
+
+func TestMemoizeKey2_Slices(t *testing.T) {
+	count := 0
+	computeFn := func(a []int, b []int) int {
+		count++
+		return len(a) * len(b)
+	}
+	memoizedFn := MemoizeKey2(computeFn, func(a []int, b []int) string {
+		return fmt.Sprint(a, b)
+	}, 0, WithShards(4))
+	memoizedFn([]int{1}, []int{2, 3})
+	memoizedFn([]int{1}, []int{2, 3})
+	if v := memoizedFn([]int{1, 2}, []int{3}); v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+}
+
+func TestMemoizeKey7(t *testing.T) {
+	count := 0
+	computeFn := func(a, b, c, d, e, f, g []int) int {
+		count++
+		return len(a) + len(b) + len(c) + len(d) + len(e) + len(f) + len(g)
+	}
+	memoizedFn := MemoizeKey7(computeFn, func(a, b, c, d, e, f, g []int) string {
+		return fmt.Sprint(a, b, c, d, e, f, g)
+	}, 0)
+	s := []int{1}
+	memoizedFn(s, s, s, s, s, s, s)
+	if v := memoizedFn(s, s, s, s, s, s, s); v != 7 {
+		t.Errorf("Expected 7, got %d", v)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+}
+
+func TestMemoizeKey1_WithTTL(t *testing.T) {
+	count := 0
+	clock := NewFakeClock()
+	memoizedFn := MemoizeKey1(func(s []byte) int {
+		count++
+		return len(s)
+	}, func(s []byte) string {
+		return string(s)
+	}, time.Second, WithClock(clock))
+	memoizedFn([]byte("ab"))
+	memoizedFn([]byte("ab"))
+	clock.Advance(2 * time.Second)
+	memoizedFn([]byte("ab"))
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+}
+
+func TestMemoizeCtxKey1(t *testing.T) {
+	count := 0
+	type ctxKey struct{}
+	computeFn := func(ctx context.Context, ids []int) string {
+		count++
+		return fmt.Sprintf("%v %v", ctx.Value(ctxKey{}), ids)
+	}
+	memoizedFn := MemoizeCtxKey1(computeFn, func(ids []int) string {
+		return fmt.Sprint(ids)
+	}, 0)
+	ctx := context.WithValue(context.Background(), ctxKey{}, "first")
+	if v := memoizedFn(ctx, []int{1, 2}); v != "first [1 2]" {
+		t.Errorf("Expected first [1 2], got %s", v)
+	}
+	if v := memoizedFn(context.Background(), []int{1, 2}); v != "first [1 2]" {
+		t.Errorf("Expected first [1 2], got %s", v)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+}
+
+func TestMemoizeCtxKey3(t *testing.T) {
+	count := 0
+	computeFn := func(ctx context.Context, a []int, b map[int]int, c string) int {
+		count++
+		return len(a) + len(b) + len(c)
+	}
+	memoizedFn := MemoizeCtxKey3(computeFn, func(a []int, b map[int]int, c string) string {
+		return fmt.Sprint(a, b, c)
+	}, 0)
+	memoizedFn(context.Background(), []int{1}, map[int]int{1: 1}, "ab")
+	if v := memoizedFn(context.Background(), []int{1}, map[int]int{1: 1}, "ab"); v != 4 {
+		t.Errorf("Expected 4, got %d", v)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+}
@@ -1,6 +1,7 @@
 package go_memoize
 
 import (
+	"math"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -8,19 +9,20 @@ import (
 )
 
 func TestMemoizeWithTTL(t *testing.T) {
+	clock := NewFakeClock()
 	count := 0
 	computeFn := func() int {
 		count++
 		return 1
 	}
-	memoizedFn := Memoize(computeFn, 1*time.Second)
+	memoizedFn := Memoize(computeFn, 1*time.Second, WithClock(clock))
 	memoizedFn()
 	memoizedFn()
 	if count != 1 {
 		t.Errorf("Expected 1, got %d", count)
 	}
 
-	time.Sleep(2 * time.Second)
+	clock.Advance(2 * time.Second)
 	memoizedFn()
 	if count != 2 {
 		t.Errorf("Expected 2, got %d", count)
@@ -29,19 +31,20 @@ func TestMemoizeWithTTL(t *testing.T) {
 }
 
 func TestMemoize1WithTTL(t *testing.T) {
+	clock := NewFakeClock()
 	count := 0
 	computeFn := func(key int) int {
 		count++
 		return key * 2
 	}
-	memoizedFn := Memoize1(computeFn, 1*time.Second)
+	memoizedFn := Memoize1(computeFn, 1*time.Second, WithClock(clock))
 	memoizedFn(21)
 	memoizedFn(21)
 	if count != 1 {
 		t.Errorf("Expected 1, got %d", count)
 	}
 
-	time.Sleep(2 * time.Second)
+	clock.Advance(2 * time.Second)
 	memoizedFn(21)
 	if count != 2 {
 		t.Errorf("Expected 2, got %d", count)
@@ -49,19 +52,20 @@ func TestMemoize1WithTTL(t *testing.T) {
 }
 
 func TestMemoize2WithTTL(t *testing.T) {
+	clock := NewFakeClock()
 	count := 0
 	computeFn := func(key1, key2 int) int {
 		count++
 		return key1 + key2
 	}
-	memoizedFn := Memoize2(computeFn, 1*time.Second)
+	memoizedFn := Memoize2(computeFn, 1*time.Second, WithClock(clock))
 	memoizedFn(20, 22)
 	memoizedFn(20, 22)
 	if count != 1 {
 		t.Errorf("Expected 1, got %d", count)
 	}
 
-	time.Sleep(2 * time.Second)
+	clock.Advance(2 * time.Second)
 	memoizedFn(20, 22)
 	if count != 2 {
 		t.Errorf("Expected 2, got %d", count)
@@ -69,19 +73,20 @@ func TestMemoize2WithTTL(t *testing.T) {
 }
 
 func TestMemoize3WithTTL(t *testing.T) {
+	clock := NewFakeClock()
 	count := 0
 	computeFn := func(key1, key2, key3 int) int {
 		count++
 		return key1 + key2 + key3
 	}
-	memoizedFn := Memoize3(computeFn, 1*time.Second)
+	memoizedFn := Memoize3(computeFn, 1*time.Second, WithClock(clock))
 	memoizedFn(10, 20, 12)
 	memoizedFn(10, 20, 12)
 	if count != 1 {
 		t.Errorf("Expected 1, got %d", count)
 	}
 
-	time.Sleep(2 * time.Second)
+	clock.Advance(2 * time.Second)
 	memoizedFn(10, 20, 12)
 	if count != 2 {
 		t.Errorf("Expected 2, got %d", count)
@@ -89,19 +94,20 @@ func TestMemoize3WithTTL(t *testing.T) {
 }
 
 func TestMemoize4WithTTL(t *testing.T) {
+	clock := NewFakeClock()
 	count := 0
 	computeFn := func(key1, key2, key3, key4 int) int {
 		count++
 		return key1 + key2 + key3 + key4
 	}
-	memoizedFn := Memoize4(computeFn, 1*time.Second)
+	memoizedFn := Memoize4(computeFn, 1*time.Second, WithClock(clock))
 	memoizedFn(10, 10, 10, 12)
 	memoizedFn(10, 10, 10, 12)
 	if count != 1 {
 		t.Errorf("Expected 1, got %d", count)
 	}
 
-	time.Sleep(2 * time.Second)
+	clock.Advance(2 * time.Second)
 	memoizedFn(10, 10, 10, 12)
 	if count != 2 {
 		t.Errorf("Expected 2, got %d", count)
@@ -181,19 +187,20 @@ func TestMemoize4WithTTL_NoExpiry(t *testing.T) {
 }
 
 func TestMemoize5WithTTL(t *testing.T) {
+	clock := NewFakeClock()
 	count := 0
 	computeFn := func(key1, key2, key3, key4, key5 int) int {
 		count++
 		return key1 + key2 + key3 + key4 + key5
 	}
-	memoizedFn := Memoize5(computeFn, 1*time.Second)
+	memoizedFn := Memoize5(computeFn, 1*time.Second, WithClock(clock))
 	memoizedFn(1, 2, 3, 4, 5)
 	memoizedFn(1, 2, 3, 4, 5)
 	if count != 1 {
 		t.Errorf("Expected 1, got %d", count)
 	}
 
-	time.Sleep(2 * time.Second)
+	clock.Advance(2 * time.Second)
 	memoizedFn(1, 2, 3, 4, 5)
 	if count != 2 {
 		t.Errorf("Expected 2, got %d", count)
@@ -201,19 +208,20 @@ func TestMemoize5WithTTL(t *testing.T) {
 }
 
 func TestMemoize6WithTTL(t *testing.T) {
+	clock := NewFakeClock()
 	count := 0
 	computeFn := func(key1, key2, key3, key4, key5, key6 int) int {
 		count++
 		return key1 + key2 + key3 + key4 + key5 + key6
 	}
-	memoizedFn := Memoize6(computeFn, 1*time.Second)
+	memoizedFn := Memoize6(computeFn, 1*time.Second, WithClock(clock))
 	memoizedFn(1, 2, 3, 4, 5, 6)
 	memoizedFn(1, 2, 3, 4, 5, 6)
 	if count != 1 {
 		t.Errorf("Expected 1, got %d", count)
 	}
 
-	time.Sleep(2 * time.Second)
+	clock.Advance(2 * time.Second)
 	memoizedFn(1, 2, 3, 4, 5, 6)
 	if count != 2 {
 		t.Errorf("Expected 2, got %d", count)
@@ -221,19 +229,20 @@ func TestMemoize6WithTTL(t *testing.T) {
 }
 
 func TestMemoize7WithTTL(t *testing.T) {
+	clock := NewFakeClock()
 	count := 0
 	computeFn := func(key1, key2, key3, key4, key5, key6, key7 int) int {
 		count++
 		return key1 + key2 + key3 + key4 + key5 + key6 + key7
 	}
-	memoizedFn := Memoize7(computeFn, 1*time.Second)
+	memoizedFn := Memoize7(computeFn, 1*time.Second, WithClock(clock))
 	memoizedFn(1, 2, 3, 4, 5, 6, 7)
 	memoizedFn(1, 2, 3, 4, 5, 6, 7)
 	if count != 1 {
 		t.Errorf("Expected 1, got %d", count)
 	}
 
-	time.Sleep(2 * time.Second)
+	clock.Advance(2 * time.Second)
 	memoizedFn(1, 2, 3, 4, 5, 6, 7)
 	if count != 2 {
 		t.Errorf("Expected 2, got %d", count)
@@ -430,3 +439,179 @@ func TestMemoize6WithTTL_ConcurrentAccess(t *testing.T) {
 		t.Errorf("Expected 1, got %d", count)
 	}
 }
+
+func TestMemoize2WithShards_ConcurrentAccess(t *testing.T) {
+	var count int32
+	computeFn := func(key1, key2 int) int {
+		atomic.AddInt32(&count, 1)
+		return key1 + key2
+	}
+	memoizedFn := Memoize2(computeFn, 1*time.Minute, WithShards(16))
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if v := memoizedFn(i%10, 1); v != i%10+1 {
+				t.Errorf("Expected %d, got %d", i%10+1, v)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if atomic.LoadInt32(&count) != 10 {
+		t.Errorf("Expected 10, got %d", count)
+	}
+}
+
+func TestMemoize1WithCapacity(t *testing.T) {
+	count := 0
+	computeFn := func(key int) int {
+		count++
+		return key * 2
+	}
+	memoizedFn := Memoize1(computeFn, 0, WithCapacity(2))
+	memoizedFn(1)
+	memoizedFn(2)
+	memoizedFn(1)
+	memoizedFn(3)
+	if count != 3 {
+		t.Errorf("Expected 3, got %d", count)
+	}
+	memoizedFn(2)
+	if count != 4 {
+		t.Errorf("Expected 4, got %d", count)
+	}
+	memoizedFn(3)
+	if count != 4 {
+		t.Errorf("Expected 4, got %d", count)
+	}
+}
+
+func TestMemoize1WithSubSecondTTL(t *testing.T) {
+	count := 0
+	computeFn := func(key int) int {
+		count++
+		return key * 2
+	}
+	memoizedFn := Memoize1(computeFn, 100*time.Millisecond)
+	memoizedFn(21)
+	memoizedFn(21)
+	if count != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	memoizedFn(21)
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+}
+
+func TestMemoize1WithStructKey(t *testing.T) {
+	type query struct {
+		table string
+		id    int
+	}
+	count := 0
+	computeFn := func(q query) string {
+		count++
+		return q.table
+	}
+	memoizedFn := Memoize1(computeFn, 0, WithShards(4))
+	memoizedFn(query{"users", 1})
+	memoizedFn(query{"users", 1})
+	memoizedFn(query{"users", 2})
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+}
+
+func TestMemoize1WithFloatKey(t *testing.T) {
+	count := 0
+	computeFn := func(key float64) float64 {
+		count++
+		return key * 2
+	}
+	m := NewMemoizer1(computeFn, 0)
+	m.Call(1.5)
+	m.Call(1.5)
+	m.Call(0)
+	m.Call(math.Copysign(0, -1))
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+
+	for i := 0; i < 3; i++ {
+		if v := m.Call(math.NaN()); !math.IsNaN(v) {
+			t.Errorf("Expected NaN, got %v", v)
+		}
+	}
+	if count != 5 {
+		t.Errorf("Expected 5, got %d", count)
+	}
+	if n := m.Len(); n != 2 {
+		t.Errorf("Expected 2, got %d", n)
+	}
+	if n := callsInFlight(m.cache); n != 0 {
+		t.Errorf("Expected 0, got %d", n)
+	}
+}
+
+func TestMemoize2WithStructFloatKey(t *testing.T) {
+	type point struct {
+		x, y float64
+	}
+	count := 0
+	computeFn := func(name string, p point) string {
+		count++
+		return name
+	}
+	m := NewMemoizer2(computeFn, 0, WithShards(4))
+	m.Call("a", point{1, 2})
+	m.Call("a", point{1, 2})
+	for i := 0; i < 3; i++ {
+		m.Call("a", point{math.NaN(), 2})
+	}
+	if count != 4 {
+		t.Errorf("Expected 4, got %d", count)
+	}
+	m.Set("a", point{math.NaN(), 2}, "b")
+	if n := m.Len(); n != 1 {
+		t.Errorf("Expected 1, got %d", n)
+	}
+	if n := callsInFlight(m.cache); n != 0 {
+		t.Errorf("Expected 0, got %d", n)
+	}
+}
+
+func TestMemoize2WithNamedAndArrayKeys(t *testing.T) {
+	type userID string
+	count := 0
+	computeFn := func(id userID, coords [2]float64) string {
+		count++
+		return string(id)
+	}
+	memoizedFn := Memoize2(computeFn, 0, WithCapacity(10), WithEvictionPolicy(TinyLFU))
+	memoizedFn("u1", [2]float64{1, 2})
+	memoizedFn("u1", [2]float64{1, 2})
+	memoizedFn("u1", [2]float64{2, 1})
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+}
+
+func TestMemoizeHitsDoNotAllocate(t *testing.T) {
+	memoized1 := Memoize1(func(k int) int { return k }, time.Minute)
+	memoized3 := Memoize3(func(a, b string, c int) int { return c }, time.Minute, WithShards(4))
+	memoizedE := MemoizeE1(func(k int) (int, error) { return k, nil }, time.Minute)
+	memoized1(1)
+	memoized3("a", "b", 1)
+	memoizedE(1)
+	if n := testing.AllocsPerRun(100, func() {
+		memoized1(1)
+		memoized3("a", "b", 1)
+		memoizedE(1)
+	}); n != 0 {
+		t.Errorf("Expected 0, got %v", n)
+	}
+}
@@ -0,0 +1,266 @@
+package go_memoize
+
+import (
+	"context"
+	"time"
+)
+
+// MemoizeTTL returns a memoized version of the compute function, caching each result for the TTL returned along with it.
+// V is the type of the value returned by the compute function.
+// Each result expires after its own TTL, e.g. the lifetime of a token, or time.Until its absolute expiry.
+// A TTL of zero means the result never expires, and a result with a negative TTL is not cached.
+// Errors are cached only as configured by WithErrorTTL and WithErrorFilter, see Cache.GetOrComputeWithTTL.
+func MemoizeTTL[V any](computeFn func() (V, time.Duration, error), opts ...Option) func() (V, error) {
+	cache := newCache[uint64, V](1, 0, nil, opts...)
+	return func() (V, error) {
+		if e := cache.lookup(0); e != nil {
+			return e.value, e.err
+		}
+		return cache.GetOrComputeWithTTL(0, func() (V, time.Duration, error) {
+			return computeFn()
+		})
+	}
+}
+
+// MemoizeTTL1 returns a memoized version of the compute function with a single key, caching each result for the TTL returned along with it.
+// K is the type of the key, and V is the type of the value returned by the compute function.
+func MemoizeTTL1[K comparable, V any](computeFn func(K) (V, time.Duration, error), opts ...Option) func(K) (V, error) {
+	cache := newCache[K, V](0, 0, nil, opts...)
+	return func(k K) (V, error) {
+		if e := cache.lookup(k); e != nil {
+			return e.value, e.err
+		}
+		return cache.GetOrComputeWithTTL(k, func() (V, time.Duration, error) {
+			return computeFn(k)
+		})
+	}
+}
+
+// MemoizeTTL2 returns a memoized version of the compute function with two keys, caching each result for the TTL returned along with it.
+// K1 and K2 are the types of the keys, and V is the type of the value returned by the compute function.
+func MemoizeTTL2[K1, K2 comparable, V any](computeFn func(K1, K2) (V, time.Duration, error), opts ...Option) func(K1, K2) (V, error) {
+	cache := newCache[args2[K1, K2], V](0, 0, hashOf[args2[K1, K2]], opts...)
+	hasher := cache.keyHasher()
+	return func(key1 K1, key2 K2) (V, error) {
+		key := newArgs2(hasher, key1, key2)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.GetOrComputeWithTTL(key, func() (V, time.Duration, error) {
+			return computeFn(key1, key2)
+		})
+	}
+}
+
+// MemoizeTTL3 returns a memoized version of the compute function with three keys, caching each result for the TTL returned along with it.
+// K1, K2, and K3 are the types of the keys, and V is the type of the value returned by the compute function.
+func MemoizeTTL3[K1, K2, K3 comparable, V any](computeFn func(K1, K2, K3) (V, time.Duration, error), opts ...Option) func(K1, K2, K3) (V, error) {
+	cache := newCache[args3[K1, K2, K3], V](0, 0, hashOf[args3[K1, K2, K3]], opts...)
+	hasher := cache.keyHasher()
+	return func(key1 K1, key2 K2, key3 K3) (V, error) {
+		key := newArgs3(hasher, key1, key2, key3)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.GetOrComputeWithTTL(key, func() (V, time.Duration, error) {
+			return computeFn(key1, key2, key3)
+		})
+	}
+}
+
+// MemoizeTTL4 returns a memoized version of the compute function with four keys, caching each result for the TTL returned along with it.
+// K1, K2, K3, and K4 are the types of the keys, and V is the type of the value returned by the compute function.
+func MemoizeTTL4[K1, K2, K3, K4 comparable, V any](computeFn func(K1, K2, K3, K4) (V, time.Duration, error), opts ...Option) func(K1, K2, K3, K4) (V, error) {
+	cache := newCache[args4[K1, K2, K3, K4], V](0, 0, hashOf[args4[K1, K2, K3, K4]], opts...)
+	hasher := cache.keyHasher()
+	return func(key1 K1, key2 K2, key3 K3, key4 K4) (V, error) {
+		key := newArgs4(hasher, key1, key2, key3, key4)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.GetOrComputeWithTTL(key, func() (V, time.Duration, error) {
+			return computeFn(key1, key2, key3, key4)
+		})
+	}
+}
+
+// MemoizeTTL5 returns a memoized version of the compute function with five keys, caching each result for the TTL returned along with it.
+// K1, K2, K3, K4, and K5 are the types of the keys, and V is the type of the value returned by the compute function.
+func MemoizeTTL5[K1, K2, K3, K4, K5 comparable, V any](computeFn func(K1, K2, K3, K4, K5) (V, time.Duration, error), opts ...Option) func(K1, K2, K3, K4, K5) (V, error) {
+	cache := newCache[args5[K1, K2, K3, K4, K5], V](0, 0, hashOf[args5[K1, K2, K3, K4, K5]], opts...)
+	hasher := cache.keyHasher()
+	return func(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5) (V, error) {
+		key := newArgs5(hasher, key1, key2, key3, key4, key5)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.GetOrComputeWithTTL(key, func() (V, time.Duration, error) {
+			return computeFn(key1, key2, key3, key4, key5)
+		})
+	}
+}
+
+// MemoizeTTL6 returns a memoized version of the compute function with six keys, caching each result for the TTL returned along with it.
+// K1, K2, K3, K4, K5, and K6 are the types of the keys, and V is the type of the value returned by the compute function.
+func MemoizeTTL6[K1, K2, K3, K4, K5, K6 comparable, V any](computeFn func(K1, K2, K3, K4, K5, K6) (V, time.Duration, error), opts ...Option) func(K1, K2, K3, K4, K5, K6) (V, error) {
+	cache := newCache[args6[K1, K2, K3, K4, K5, K6], V](0, 0, hashOf[args6[K1, K2, K3, K4, K5, K6]], opts...)
+	hasher := cache.keyHasher()
+	return func(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6) (V, error) {
+		key := newArgs6(hasher, key1, key2, key3, key4, key5, key6)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.GetOrComputeWithTTL(key, func() (V, time.Duration, error) {
+			return computeFn(key1, key2, key3, key4, key5, key6)
+		})
+	}
+}
+
+// MemoizeTTL7 returns a memoized version of the compute function with seven keys, caching each result for the TTL returned along with it.
+// K1, K2, K3, K4, K5, K6, and K7 are the types of the keys, and V is the type of the value returned by the compute function.
+func MemoizeTTL7[K1, K2, K3, K4, K5, K6, K7 comparable, V any](computeFn func(K1, K2, K3, K4, K5, K6, K7) (V, time.Duration, error), opts ...Option) func(K1, K2, K3, K4, K5, K6, K7) (V, error) {
+	cache := newCache[args7[K1, K2, K3, K4, K5, K6, K7], V](0, 0, hashOf[args7[K1, K2, K3, K4, K5, K6, K7]], opts...)
+	hasher := cache.keyHasher()
+	return func(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, key7 K7) (V, error) {
+		key := newArgs7(hasher, key1, key2, key3, key4, key5, key6, key7)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.GetOrComputeWithTTL(key, func() (V, time.Duration, error) {
+			return computeFn(key1, key2, key3, key4, key5, key6, key7)
+		})
+	}
+}
+
+// MemoizeCtxTTL returns a memoized version of the compute function with context, caching each result for the TTL returned along with it.
+// Refreshes in the background use the context of the caller without its cancellation, see MemoizeTTL.
+func MemoizeCtxTTL[V any](computeFn func(context.Context) (V, time.Duration, error), opts ...Option) func(context.Context) (V, error) {
+	cache := newCache[uint64, V](1, 0, nil, opts...)
+	return func(ctx context.Context) (V, error) {
+		if e := cache.lookup(0); e != nil {
+			return e.value, e.err
+		}
+		return cache.getOrComputeWith(0, computation[V]{ttlFn: func() (V, time.Duration, error) {
+			return computeFn(ctx)
+		}, ctx: ctx}, computation[V]{ttlFn: func() (V, time.Duration, error) {
+			return computeFn(context.WithoutCancel(ctx))
+		}})
+	}
+}
+
+// MemoizeCtxTTL1 returns a memoized version of the compute function with context and a single key, caching each result for the TTL returned along with it.
+func MemoizeCtxTTL1[K comparable, V any](computeFn func(context.Context, K) (V, time.Duration, error), opts ...Option) func(context.Context, K) (V, error) {
+	cache := newCache[K, V](0, 0, nil, opts...)
+	return func(ctx context.Context, k K) (V, error) {
+		if e := cache.lookup(k); e != nil {
+			return e.value, e.err
+		}
+		return cache.getOrComputeWith(k, computation[V]{ttlFn: func() (V, time.Duration, error) {
+			return computeFn(ctx, k)
+		}, ctx: ctx}, computation[V]{ttlFn: func() (V, time.Duration, error) {
+			return computeFn(context.WithoutCancel(ctx), k)
+		}})
+	}
+}
+
+// MemoizeCtxTTL2 returns a memoized version of the compute function with context and two keys, caching each result for the TTL returned along with it.
+func MemoizeCtxTTL2[K1, K2 comparable, V any](computeFn func(context.Context, K1, K2) (V, time.Duration, error), opts ...Option) func(context.Context, K1, K2) (V, error) {
+	cache := newCache[args2[K1, K2], V](0, 0, hashOf[args2[K1, K2]], opts...)
+	hasher := cache.keyHasher()
+	return func(ctx context.Context, key1 K1, key2 K2) (V, error) {
+		key := newArgs2(hasher, key1, key2)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.getOrComputeWith(key, computation[V]{ttlFn: func() (V, time.Duration, error) {
+			return computeFn(ctx, key1, key2)
+		}, ctx: ctx}, computation[V]{ttlFn: func() (V, time.Duration, error) {
+			return computeFn(context.WithoutCancel(ctx), key1, key2)
+		}})
+	}
+}
+
+// MemoizeCtxTTL3 returns a memoized version of the compute function with context and three keys, caching each result for the TTL returned along with it.
+func MemoizeCtxTTL3[K1, K2, K3 comparable, V any](computeFn func(context.Context, K1, K2, K3) (V, time.Duration, error), opts ...Option) func(context.Context, K1, K2, K3) (V, error) {
+	cache := newCache[args3[K1, K2, K3], V](0, 0, hashOf[args3[K1, K2, K3]], opts...)
+	hasher := cache.keyHasher()
+	return func(ctx context.Context, key1 K1, key2 K2, key3 K3) (V, error) {
+		key := newArgs3(hasher, key1, key2, key3)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.getOrComputeWith(key, computation[V]{ttlFn: func() (V, time.Duration, error) {
+			return computeFn(ctx, key1, key2, key3)
+		}, ctx: ctx}, computation[V]{ttlFn: func() (V, time.Duration, error) {
+			return computeFn(context.WithoutCancel(ctx), key1, key2, key3)
+		}})
+	}
+}
+
+// MemoizeCtxTTL4 returns a memoized version of the compute function with context and four keys, caching each result for the TTL returned along with it.
+func MemoizeCtxTTL4[K1, K2, K3, K4 comparable, V any](computeFn func(context.Context, K1, K2, K3, K4) (V, time.Duration, error), opts ...Option) func(context.Context, K1, K2, K3, K4) (V, error) {
+	cache := newCache[args4[K1, K2, K3, K4], V](0, 0, hashOf[args4[K1, K2, K3, K4]], opts...)
+	hasher := cache.keyHasher()
+	return func(ctx context.Context, key1 K1, key2 K2, key3 K3, key4 K4) (V, error) {
+		key := newArgs4(hasher, key1, key2, key3, key4)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.getOrComputeWith(key, computation[V]{ttlFn: func() (V, time.Duration, error) {
+			return computeFn(ctx, key1, key2, key3, key4)
+		}, ctx: ctx}, computation[V]{ttlFn: func() (V, time.Duration, error) {
+			return computeFn(context.WithoutCancel(ctx), key1, key2, key3, key4)
+		}})
+	}
+}
+
+// MemoizeCtxTTL5 returns a memoized version of the compute function with context and five keys, caching each result for the TTL returned along with it.
+func MemoizeCtxTTL5[K1, K2, K3, K4, K5 comparable, V any](computeFn func(context.Context, K1, K2, K3, K4, K5) (V, time.Duration, error), opts ...Option) func(context.Context, K1, K2, K3, K4, K5) (V, error) {
+	cache := newCache[args5[K1, K2, K3, K4, K5], V](0, 0, hashOf[args5[K1, K2, K3, K4, K5]], opts...)
+	hasher := cache.keyHasher()
+	return func(ctx context.Context, key1 K1, key2 K2, key3 K3, key4 K4, key5 K5) (V, error) {
+		key := newArgs5(hasher, key1, key2, key3, key4, key5)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.getOrComputeWith(key, computation[V]{ttlFn: func() (V, time.Duration, error) {
+			return computeFn(ctx, key1, key2, key3, key4, key5)
+		}, ctx: ctx}, computation[V]{ttlFn: func() (V, time.Duration, error) {
+			return computeFn(context.WithoutCancel(ctx), key1, key2, key3, key4, key5)
+		}})
+	}
+}
+
+// MemoizeCtxTTL6 returns a memoized version of the compute function with context and six keys, caching each result for the TTL returned along with it.
+func MemoizeCtxTTL6[K1, K2, K3, K4, K5, K6 comparable, V any](computeFn func(context.Context, K1, K2, K3, K4, K5, K6) (V, time.Duration, error), opts ...Option) func(context.Context, K1, K2, K3, K4, K5, K6) (V, error) {
+	cache := newCache[args6[K1, K2, K3, K4, K5, K6], V](0, 0, hashOf[args6[K1, K2, K3, K4, K5, K6]], opts...)
+	hasher := cache.keyHasher()
+	return func(ctx context.Context, key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6) (V, error) {
+		key := newArgs6(hasher, key1, key2, key3, key4, key5, key6)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.getOrComputeWith(key, computation[V]{ttlFn: func() (V, time.Duration, error) {
+			return computeFn(ctx, key1, key2, key3, key4, key5, key6)
+		}, ctx: ctx}, computation[V]{ttlFn: func() (V, time.Duration, error) {
+			return computeFn(context.WithoutCancel(ctx), key1, key2, key3, key4, key5, key6)
+		}})
+	}
+}
+
+// MemoizeCtxTTL7 returns a memoized version of the compute function with context and seven keys, caching each result for the TTL returned along with it.
+func MemoizeCtxTTL7[K1, K2, K3, K4, K5, K6, K7 comparable, V any](computeFn func(context.Context, K1, K2, K3, K4, K5, K6, K7) (V, time.Duration, error), opts ...Option) func(context.Context, K1, K2, K3, K4, K5, K6, K7) (V, error) {
+	cache := newCache[args7[K1, K2, K3, K4, K5, K6, K7], V](0, 0, hashOf[args7[K1, K2, K3, K4, K5, K6, K7]], opts...)
+	hasher := cache.keyHasher()
+	return func(ctx context.Context, key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, key7 K7) (V, error) {
+		key := newArgs7(hasher, key1, key2, key3, key4, key5, key6, key7)
+		if e := cache.lookup(key); e != nil {
+			return e.value, e.err
+		}
+		return cache.getOrComputeWith(key, computation[V]{ttlFn: func() (V, time.Duration, error) {
+			return computeFn(ctx, key1, key2, key3, key4, key5, key6, key7)
+		}, ctx: ctx}, computation[V]{ttlFn: func() (V, time.Duration, error) {
+			return computeFn(context.WithoutCancel(ctx), key1, key2, key3, key4, key5, key6, key7)
+		}})
+	}
+}
@@ -0,0 +1,110 @@
+package go_memoize
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoizeTTL(t *testing.T) {
+	clock := NewFakeClock()
+	count := 0
+	memoizedFn := MemoizeTTL(func() (int, time.Duration, error) {
+		count++
+		return count, time.Second, nil
+	}, WithClock(clock))
+	memoizedFn()
+	if v, _ := memoizedFn(); v != 1 {
+		t.Errorf("Expected 1, got %d", v)
+	}
+	clock.Advance(2 * time.Second)
+	if v, _ := memoizedFn(); v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+}
+
+func TestMemoizeTTL1_ExpiresEachResultAfterItsTTL(t *testing.T) {
+	clock := NewFakeClock()
+	count := 0
+	lifetimes := map[string]time.Duration{"short": time.Second, "long": time.Hour, "forever": 0}
+	memoizedFn := MemoizeTTL1(func(token string) (int, time.Duration, error) {
+		count++
+		return count, lifetimes[token], nil
+	}, WithClock(clock))
+	memoizedFn("short")
+	memoizedFn("long")
+	memoizedFn("forever")
+
+	clock.Advance(2 * time.Second)
+	if v, _ := memoizedFn("short"); v != 4 {
+		t.Errorf("Expected 4, got %d", v)
+	}
+	if v, _ := memoizedFn("long"); v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+	clock.Advance(2 * time.Hour)
+	if v, _ := memoizedFn("long"); v != 5 {
+		t.Errorf("Expected 5, got %d", v)
+	}
+	if v, _ := memoizedFn("forever"); v != 3 {
+		t.Errorf("Expected 3, got %d", v)
+	}
+}
+
+func TestMemoizeTTL1_AbsoluteExpiry(t *testing.T) {
+	expiresAt := time.Now().Add(-time.Minute)
+	count := 0
+	memoizedFn := MemoizeTTL1(func(k int) (int, time.Duration, error) {
+		count++
+		return count, time.Until(expiresAt), nil
+	})
+	memoizedFn(1)
+	if v, _ := memoizedFn(1); v != 2 {
+		t.Errorf("Expected an already expired result not to be cached, got %d", v)
+	}
+}
+
+func TestMemoizeTTL2_DoesNotCacheErrors(t *testing.T) {
+	count := 0
+	memoizedFn := MemoizeTTL2(func(a, b int) (int, time.Duration, error) {
+		count++
+		return 0, time.Minute, errCompute
+	})
+	memoizedFn(1, 2)
+	if _, err := memoizedFn(1, 2); !errors.Is(err, errCompute) {
+		t.Errorf("Expected %v, got %v", errCompute, err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+}
+
+func TestMemoizeTTL7(t *testing.T) {
+	memoizedFn := MemoizeTTL7(func(a, b, c, d, e, f, g int) (int, time.Duration, error) {
+		return a + b + c + d + e + f + g, time.Minute, nil
+	})
+	if v, _ := memoizedFn(1, 2, 3, 4, 5, 6, 7); v != 28 {
+		t.Errorf("Expected 28, got %d", v)
+	}
+}
+
+func TestMemoizeCtxTTL1(t *testing.T) {
+	type ctxKey struct{}
+	clock := NewFakeClock()
+	count := 0
+	memoizedFn := MemoizeCtxTTL1(func(ctx context.Context, k string) (string, time.Duration, error) {
+		count++
+		return ctx.Value(ctxKey{}).(string) + k, time.Second, nil
+	}, WithClock(clock))
+	ctx := context.WithValue(context.Background(), ctxKey{}, "hello ")
+	memoizedFn(ctx, "world")
+	if v, _ := memoizedFn(ctx, "world"); v != "hello world" || count != 1 {
+		t.Errorf("Expected hello world computed once, got %q computed %d times", v, count)
+	}
+	clock.Advance(2 * time.Second)
+	memoizedFn(ctx, "world")
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+}
@@ -0,0 +1,616 @@
+package go_memoize
+
+import (
+	"time"
+)
+
+// Memoizer is a memoized function without arguments, along with control over its cache.
+type Memoizer[V any] struct {
+	cache     *Cache[uint64, V]
+	computeFn func() V
+}
+
+// NewMemoizer returns a Memoizer memoizing the compute function with a specified TTL.
+// V is the type of the value returned by the compute function.
+func NewMemoizer[V any](computeFn func() V, ttl time.Duration, opts ...Option) *Memoizer[V] {
+	cache := newCache[uint64, V](1, ttl, nil, opts...)
+	return &Memoizer[V]{cache: cache, computeFn: computeFn}
+}
+
+// Call returns the memoized result, computing it if not present or expired.
+func (m *Memoizer[V]) Call() V {
+	if e := m.cache.lookup(0); e != nil {
+		return e.value
+	}
+	// the compute function may be kept by a refresh-ahead entry: capturing m would keep the cache from being collected
+	computeFn := m.computeFn
+	return m.cache.GetOrCompute(0, func() V {
+		return computeFn()
+	})
+}
+
+// Invalidate removes the cached result, so that the next call computes it again.
+func (m *Memoizer[V]) Invalidate() {
+	m.cache.Delete(0)
+}
+
+// Purge removes all cached results.
+func (m *Memoizer[V]) Purge() {
+	m.cache.Purge()
+}
+
+// Len returns the number of cached results, including expired ones the janitor has not removed yet.
+func (m *Memoizer[V]) Len() int {
+	return m.cache.Len()
+}
+
+// Peek returns the cached result if present and not expired, without computing it or counting as a use.
+func (m *Memoizer[V]) Peek() (V, bool) {
+	return m.cache.peek(0)
+}
+
+// Set caches value as the result.
+func (m *Memoizer[V]) Set(value V) {
+	m.cache.Set(0, value)
+}
+
+// AddListener registers fn to be called when the result is cached, replaced or removed, see Cache.AddListener.
+func (m *Memoizer[V]) AddListener(fn func(value, old V, reason EventReason)) {
+	m.cache.AddListener(func(e Event[uint64, V]) {
+		fn(e.Value, e.Old, e.Reason)
+	})
+}
+
+// Close stops the background work of the cache, see Cache.Close.
+func (m *Memoizer[V]) Close() {
+	m.cache.Close()
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (m *Memoizer[V]) Stats() Stats {
+	return m.cache.Stats()
+}
+
+// ResetStats sets the counters of the cache to zero.
+func (m *Memoizer[V]) ResetStats() {
+	m.cache.ResetStats()
+}
+
+// Memoizer1 is a memoized function with a single key, along with control over its cache.
+type Memoizer1[K comparable, V any] struct {
+	cache     *Cache[K, V]
+	computeFn func(K) V
+}
+
+// NewMemoizer1 returns a Memoizer1 memoizing the compute function with a single key and a specified TTL.
+// K is the type of the key, and V is the type of the value returned by the compute function.
+func NewMemoizer1[K comparable, V any](computeFn func(K) V, ttl time.Duration, opts ...Option) *Memoizer1[K, V] {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return &Memoizer1[K, V]{cache: cache, computeFn: computeFn}
+}
+
+// Call returns the memoized result for the given key, computing it if not present or expired.
+func (m *Memoizer1[K, V]) Call(k K) V {
+	if e := m.cache.lookup(k); e != nil {
+		return e.value
+	}
+	computeFn := m.computeFn
+	return m.cache.GetOrCompute(k, func() V {
+		return computeFn(k)
+	})
+}
+
+// Invalidate removes the cached result for the given key, so that the next call computes it again.
+func (m *Memoizer1[K, V]) Invalidate(k K) {
+	m.cache.Delete(k)
+}
+
+// Purge removes all cached results.
+func (m *Memoizer1[K, V]) Purge() {
+	m.cache.Purge()
+}
+
+// Len returns the number of cached results, including expired ones the janitor has not removed yet.
+func (m *Memoizer1[K, V]) Len() int {
+	return m.cache.Len()
+}
+
+// Peek returns the cached result for the given key if present and not expired, without computing it or counting as a use.
+func (m *Memoizer1[K, V]) Peek(k K) (V, bool) {
+	return m.cache.peek(k)
+}
+
+// Set caches value as the result for the given key.
+func (m *Memoizer1[K, V]) Set(k K, value V) {
+	m.cache.Set(k, value)
+}
+
+// AddListener registers fn to be called when a result is cached, replaced or removed, with its key, see Cache.AddListener.
+func (m *Memoizer1[K, V]) AddListener(fn func(k K, value, old V, reason EventReason)) {
+	m.cache.AddListener(func(e Event[K, V]) {
+		fn(e.Key, e.Value, e.Old, e.Reason)
+	})
+}
+
+// Close stops the background work of the cache, see Cache.Close.
+func (m *Memoizer1[K, V]) Close() {
+	m.cache.Close()
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (m *Memoizer1[K, V]) Stats() Stats {
+	return m.cache.Stats()
+}
+
+// ResetStats sets the counters of the cache to zero.
+func (m *Memoizer1[K, V]) ResetStats() {
+	m.cache.ResetStats()
+}
+
+// Memoizer2 is a memoized function with two keys, along with control over its cache.
+type Memoizer2[K1, K2 comparable, V any] struct {
+	cache     *Cache[args2[K1, K2], V]
+	computeFn func(K1, K2) V
+	hasher    Hasher
+}
+
+// NewMemoizer2 returns a Memoizer2 memoizing the compute function with two keys and a specified TTL.
+// K1 and K2 are the types of the keys, and V is the type of the value returned by the compute function.
+func NewMemoizer2[K1, K2 comparable, V any](computeFn func(K1, K2) V, ttl time.Duration, opts ...Option) *Memoizer2[K1, K2, V] {
+	cache := newCache[args2[K1, K2], V](0, ttl, hashOf[args2[K1, K2]], opts...)
+	return &Memoizer2[K1, K2, V]{cache: cache, computeFn: computeFn, hasher: cache.keyHasher()}
+}
+
+// key returns the cache key of the arguments.
+func (m *Memoizer2[K1, K2, V]) key(key1 K1, key2 K2) args2[K1, K2] {
+	return newArgs2(m.hasher, key1, key2)
+}
+
+// Call returns the memoized result for the given keys, computing it if not present or expired.
+func (m *Memoizer2[K1, K2, V]) Call(key1 K1, key2 K2) V {
+	key := m.key(key1, key2)
+	if e := m.cache.lookup(key); e != nil {
+		return e.value
+	}
+	computeFn := m.computeFn
+	return m.cache.GetOrCompute(key, func() V {
+		return computeFn(key1, key2)
+	})
+}
+
+// Invalidate removes the cached result for the given keys, so that the next call computes it again.
+func (m *Memoizer2[K1, K2, V]) Invalidate(key1 K1, key2 K2) {
+	m.cache.Delete(m.key(key1, key2))
+}
+
+// Purge removes all cached results.
+func (m *Memoizer2[K1, K2, V]) Purge() {
+	m.cache.Purge()
+}
+
+// Len returns the number of cached results, including expired ones the janitor has not removed yet.
+func (m *Memoizer2[K1, K2, V]) Len() int {
+	return m.cache.Len()
+}
+
+// Peek returns the cached result for the given keys if present and not expired, without computing it or counting as a use.
+func (m *Memoizer2[K1, K2, V]) Peek(key1 K1, key2 K2) (V, bool) {
+	return m.cache.peek(m.key(key1, key2))
+}
+
+// Set caches value as the result for the given keys.
+func (m *Memoizer2[K1, K2, V]) Set(key1 K1, key2 K2, value V) {
+	m.cache.Set(m.key(key1, key2), value)
+}
+
+// AddListener registers fn to be called when a result is cached, replaced or removed, with its keys, see Cache.AddListener.
+func (m *Memoizer2[K1, K2, V]) AddListener(fn func(key1 K1, key2 K2, value, old V, reason EventReason)) {
+	m.cache.AddListener(func(e Event[args2[K1, K2], V]) {
+		fn(e.Key.k1, e.Key.k2, e.Value, e.Old, e.Reason)
+	})
+}
+
+// Close stops the background work of the cache, see Cache.Close.
+func (m *Memoizer2[K1, K2, V]) Close() {
+	m.cache.Close()
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (m *Memoizer2[K1, K2, V]) Stats() Stats {
+	return m.cache.Stats()
+}
+
+// ResetStats sets the counters of the cache to zero.
+func (m *Memoizer2[K1, K2, V]) ResetStats() {
+	m.cache.ResetStats()
+}
+
+// Memoizer3 is a memoized function with three keys, along with control over its cache.
+type Memoizer3[K1, K2, K3 comparable, V any] struct {
+	cache     *Cache[args3[K1, K2, K3], V]
+	computeFn func(K1, K2, K3) V
+	hasher    Hasher
+}
+
+// NewMemoizer3 returns a Memoizer3 memoizing the compute function with three keys and a specified TTL.
+// K1, K2, and K3 are the types of the keys, and V is the type of the value returned by the compute function.
+func NewMemoizer3[K1, K2, K3 comparable, V any](computeFn func(K1, K2, K3) V, ttl time.Duration, opts ...Option) *Memoizer3[K1, K2, K3, V] {
+	cache := newCache[args3[K1, K2, K3], V](0, ttl, hashOf[args3[K1, K2, K3]], opts...)
+	return &Memoizer3[K1, K2, K3, V]{cache: cache, computeFn: computeFn, hasher: cache.keyHasher()}
+}
+
+// key returns the cache key of the arguments.
+func (m *Memoizer3[K1, K2, K3, V]) key(key1 K1, key2 K2, key3 K3) args3[K1, K2, K3] {
+	return newArgs3(m.hasher, key1, key2, key3)
+}
+
+// Call returns the memoized result for the given keys, computing it if not present or expired.
+func (m *Memoizer3[K1, K2, K3, V]) Call(key1 K1, key2 K2, key3 K3) V {
+	key := m.key(key1, key2, key3)
+	if e := m.cache.lookup(key); e != nil {
+		return e.value
+	}
+	computeFn := m.computeFn
+	return m.cache.GetOrCompute(key, func() V {
+		return computeFn(key1, key2, key3)
+	})
+}
+
+// Invalidate removes the cached result for the given keys, so that the next call computes it again.
+func (m *Memoizer3[K1, K2, K3, V]) Invalidate(key1 K1, key2 K2, key3 K3) {
+	m.cache.Delete(m.key(key1, key2, key3))
+}
+
+// Purge removes all cached results.
+func (m *Memoizer3[K1, K2, K3, V]) Purge() {
+	m.cache.Purge()
+}
+
+// Len returns the number of cached results, including expired ones the janitor has not removed yet.
+func (m *Memoizer3[K1, K2, K3, V]) Len() int {
+	return m.cache.Len()
+}
+
+// Peek returns the cached result for the given keys if present and not expired, without computing it or counting as a use.
+func (m *Memoizer3[K1, K2, K3, V]) Peek(key1 K1, key2 K2, key3 K3) (V, bool) {
+	return m.cache.peek(m.key(key1, key2, key3))
+}
+
+// Set caches value as the result for the given keys.
+func (m *Memoizer3[K1, K2, K3, V]) Set(key1 K1, key2 K2, key3 K3, value V) {
+	m.cache.Set(m.key(key1, key2, key3), value)
+}
+
+// AddListener registers fn to be called when a result is cached, replaced or removed, with its keys, see Cache.AddListener.
+func (m *Memoizer3[K1, K2, K3, V]) AddListener(fn func(key1 K1, key2 K2, key3 K3, value, old V, reason EventReason)) {
+	m.cache.AddListener(func(e Event[args3[K1, K2, K3], V]) {
+		fn(e.Key.k1, e.Key.k2, e.Key.k3, e.Value, e.Old, e.Reason)
+	})
+}
+
+// Close stops the background work of the cache, see Cache.Close.
+func (m *Memoizer3[K1, K2, K3, V]) Close() {
+	m.cache.Close()
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (m *Memoizer3[K1, K2, K3, V]) Stats() Stats {
+	return m.cache.Stats()
+}
+
+// ResetStats sets the counters of the cache to zero.
+func (m *Memoizer3[K1, K2, K3, V]) ResetStats() {
+	m.cache.ResetStats()
+}
+
+// Memoizer4 is a memoized function with four keys, along with control over its cache.
+type Memoizer4[K1, K2, K3, K4 comparable, V any] struct {
+	cache     *Cache[args4[K1, K2, K3, K4], V]
+	computeFn func(K1, K2, K3, K4) V
+	hasher    Hasher
+}
+
+// NewMemoizer4 returns a Memoizer4 memoizing the compute function with four keys and a specified TTL.
+// K1, K2, K3, and K4 are the types of the keys, and V is the type of the value returned by the compute function.
+func NewMemoizer4[K1, K2, K3, K4 comparable, V any](computeFn func(K1, K2, K3, K4) V, ttl time.Duration, opts ...Option) *Memoizer4[K1, K2, K3, K4, V] {
+	cache := newCache[args4[K1, K2, K3, K4], V](0, ttl, hashOf[args4[K1, K2, K3, K4]], opts...)
+	return &Memoizer4[K1, K2, K3, K4, V]{cache: cache, computeFn: computeFn, hasher: cache.keyHasher()}
+}
+
+// key returns the cache key of the arguments.
+func (m *Memoizer4[K1, K2, K3, K4, V]) key(key1 K1, key2 K2, key3 K3, key4 K4) args4[K1, K2, K3, K4] {
+	return newArgs4(m.hasher, key1, key2, key3, key4)
+}
+
+// Call returns the memoized result for the given keys, computing it if not present or expired.
+func (m *Memoizer4[K1, K2, K3, K4, V]) Call(key1 K1, key2 K2, key3 K3, key4 K4) V {
+	key := m.key(key1, key2, key3, key4)
+	if e := m.cache.lookup(key); e != nil {
+		return e.value
+	}
+	computeFn := m.computeFn
+	return m.cache.GetOrCompute(key, func() V {
+		return computeFn(key1, key2, key3, key4)
+	})
+}
+
+// Invalidate removes the cached result for the given keys, so that the next call computes it again.
+func (m *Memoizer4[K1, K2, K3, K4, V]) Invalidate(key1 K1, key2 K2, key3 K3, key4 K4) {
+	m.cache.Delete(m.key(key1, key2, key3, key4))
+}
+
+// Purge removes all cached results.
+func (m *Memoizer4[K1, K2, K3, K4, V]) Purge() {
+	m.cache.Purge()
+}
+
+// Len returns the number of cached results, including expired ones the janitor has not removed yet.
+func (m *Memoizer4[K1, K2, K3, K4, V]) Len() int {
+	return m.cache.Len()
+}
+
+// Peek returns the cached result for the given keys if present and not expired, without computing it or counting as a use.
+func (m *Memoizer4[K1, K2, K3, K4, V]) Peek(key1 K1, key2 K2, key3 K3, key4 K4) (V, bool) {
+	return m.cache.peek(m.key(key1, key2, key3, key4))
+}
+
+// Set caches value as the result for the given keys.
+func (m *Memoizer4[K1, K2, K3, K4, V]) Set(key1 K1, key2 K2, key3 K3, key4 K4, value V) {
+	m.cache.Set(m.key(key1, key2, key3, key4), value)
+}
+
+// AddListener registers fn to be called when a result is cached, replaced or removed, with its keys, see Cache.AddListener.
+func (m *Memoizer4[K1, K2, K3, K4, V]) AddListener(fn func(key1 K1, key2 K2, key3 K3, key4 K4, value, old V, reason EventReason)) {
+	m.cache.AddListener(func(e Event[args4[K1, K2, K3, K4], V]) {
+		fn(e.Key.k1, e.Key.k2, e.Key.k3, e.Key.k4, e.Value, e.Old, e.Reason)
+	})
+}
+
+// Close stops the background work of the cache, see Cache.Close.
+func (m *Memoizer4[K1, K2, K3, K4, V]) Close() {
+	m.cache.Close()
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (m *Memoizer4[K1, K2, K3, K4, V]) Stats() Stats {
+	return m.cache.Stats()
+}
+
+// ResetStats sets the counters of the cache to zero.
+func (m *Memoizer4[K1, K2, K3, K4, V]) ResetStats() {
+	m.cache.ResetStats()
+}
+
+// Memoizer5 is a memoized function with five keys, along with control over its cache.
+type Memoizer5[K1, K2, K3, K4, K5 comparable, V any] struct {
+	cache     *Cache[args5[K1, K2, K3, K4, K5], V]
+	computeFn func(K1, K2, K3, K4, K5) V
+	hasher    Hasher
+}
+
+// NewMemoizer5 returns a Memoizer5 memoizing the compute function with five keys and a specified TTL.
+// K1, K2, K3, K4, and K5 are the types of the keys, and V is the type of the value returned by the compute function.
+func NewMemoizer5[K1, K2, K3, K4, K5 comparable, V any](computeFn func(K1, K2, K3, K4, K5) V, ttl time.Duration, opts ...Option) *Memoizer5[K1, K2, K3, K4, K5, V] {
+	cache := newCache[args5[K1, K2, K3, K4, K5], V](0, ttl, hashOf[args5[K1, K2, K3, K4, K5]], opts...)
+	return &Memoizer5[K1, K2, K3, K4, K5, V]{cache: cache, computeFn: computeFn, hasher: cache.keyHasher()}
+}
+
+// key returns the cache key of the arguments.
+func (m *Memoizer5[K1, K2, K3, K4, K5, V]) key(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5) args5[K1, K2, K3, K4, K5] {
+	return newArgs5(m.hasher, key1, key2, key3, key4, key5)
+}
+
+// Call returns the memoized result for the given keys, computing it if not present or expired.
+func (m *Memoizer5[K1, K2, K3, K4, K5, V]) Call(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5) V {
+	key := m.key(key1, key2, key3, key4, key5)
+	if e := m.cache.lookup(key); e != nil {
+		return e.value
+	}
+	computeFn := m.computeFn
+	return m.cache.GetOrCompute(key, func() V {
+		return computeFn(key1, key2, key3, key4, key5)
+	})
+}
+
+// Invalidate removes the cached result for the given keys, so that the next call computes it again.
+func (m *Memoizer5[K1, K2, K3, K4, K5, V]) Invalidate(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5) {
+	m.cache.Delete(m.key(key1, key2, key3, key4, key5))
+}
+
+// Purge removes all cached results.
+func (m *Memoizer5[K1, K2, K3, K4, K5, V]) Purge() {
+	m.cache.Purge()
+}
+
+// Len returns the number of cached results, including expired ones the janitor has not removed yet.
+func (m *Memoizer5[K1, K2, K3, K4, K5, V]) Len() int {
+	return m.cache.Len()
+}
+
+// Peek returns the cached result for the given keys if present and not expired, without computing it or counting as a use.
+func (m *Memoizer5[K1, K2, K3, K4, K5, V]) Peek(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5) (V, bool) {
+	return m.cache.peek(m.key(key1, key2, key3, key4, key5))
+}
+
+// Set caches value as the result for the given keys.
+func (m *Memoizer5[K1, K2, K3, K4, K5, V]) Set(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, value V) {
+	m.cache.Set(m.key(key1, key2, key3, key4, key5), value)
+}
+
+// AddListener registers fn to be called when a result is cached, replaced or removed, with its keys, see Cache.AddListener.
+func (m *Memoizer5[K1, K2, K3, K4, K5, V]) AddListener(fn func(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, value, old V, reason EventReason)) {
+	m.cache.AddListener(func(e Event[args5[K1, K2, K3, K4, K5], V]) {
+		fn(e.Key.k1, e.Key.k2, e.Key.k3, e.Key.k4, e.Key.k5, e.Value, e.Old, e.Reason)
+	})
+}
+
+// Close stops the background work of the cache, see Cache.Close.
+func (m *Memoizer5[K1, K2, K3, K4, K5, V]) Close() {
+	m.cache.Close()
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (m *Memoizer5[K1, K2, K3, K4, K5, V]) Stats() Stats {
+	return m.cache.Stats()
+}
+
+// ResetStats sets the counters of the cache to zero.
+func (m *Memoizer5[K1, K2, K3, K4, K5, V]) ResetStats() {
+	m.cache.ResetStats()
+}
+
+// Memoizer6 is a memoized function with six keys, along with control over its cache.
+type Memoizer6[K1, K2, K3, K4, K5, K6 comparable, V any] struct {
+	cache     *Cache[args6[K1, K2, K3, K4, K5, K6], V]
+	computeFn func(K1, K2, K3, K4, K5, K6) V
+	hasher    Hasher
+}
+
+// NewMemoizer6 returns a Memoizer6 memoizing the compute function with six keys and a specified TTL.
+// K1, K2, K3, K4, K5, and K6 are the types of the keys, and V is the type of the value returned by the compute function.
+func NewMemoizer6[K1, K2, K3, K4, K5, K6 comparable, V any](computeFn func(K1, K2, K3, K4, K5, K6) V, ttl time.Duration, opts ...Option) *Memoizer6[K1, K2, K3, K4, K5, K6, V] {
+	cache := newCache[args6[K1, K2, K3, K4, K5, K6], V](0, ttl, hashOf[args6[K1, K2, K3, K4, K5, K6]], opts...)
+	return &Memoizer6[K1, K2, K3, K4, K5, K6, V]{cache: cache, computeFn: computeFn, hasher: cache.keyHasher()}
+}
+
+// key returns the cache key of the arguments.
+func (m *Memoizer6[K1, K2, K3, K4, K5, K6, V]) key(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6) args6[K1, K2, K3, K4, K5, K6] {
+	return newArgs6(m.hasher, key1, key2, key3, key4, key5, key6)
+}
+
+// Call returns the memoized result for the given keys, computing it if not present or expired.
+func (m *Memoizer6[K1, K2, K3, K4, K5, K6, V]) Call(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6) V {
+	key := m.key(key1, key2, key3, key4, key5, key6)
+	if e := m.cache.lookup(key); e != nil {
+		return e.value
+	}
+	computeFn := m.computeFn
+	return m.cache.GetOrCompute(key, func() V {
+		return computeFn(key1, key2, key3, key4, key5, key6)
+	})
+}
+
+// Invalidate removes the cached result for the given keys, so that the next call computes it again.
+func (m *Memoizer6[K1, K2, K3, K4, K5, K6, V]) Invalidate(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6) {
+	m.cache.Delete(m.key(key1, key2, key3, key4, key5, key6))
+}
+
+// Purge removes all cached results.
+func (m *Memoizer6[K1, K2, K3, K4, K5, K6, V]) Purge() {
+	m.cache.Purge()
+}
+
+// Len returns the number of cached results, including expired ones the janitor has not removed yet.
+func (m *Memoizer6[K1, K2, K3, K4, K5, K6, V]) Len() int {
+	return m.cache.Len()
+}
+
+// Peek returns the cached result for the given keys if present and not expired, without computing it or counting as a use.
+func (m *Memoizer6[K1, K2, K3, K4, K5, K6, V]) Peek(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6) (V, bool) {
+	return m.cache.peek(m.key(key1, key2, key3, key4, key5, key6))
+}
+
+// Set caches value as the result for the given keys.
+func (m *Memoizer6[K1, K2, K3, K4, K5, K6, V]) Set(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, value V) {
+	m.cache.Set(m.key(key1, key2, key3, key4, key5, key6), value)
+}
+
+// AddListener registers fn to be called when a result is cached, replaced or removed, with its keys, see Cache.AddListener.
+func (m *Memoizer6[K1, K2, K3, K4, K5, K6, V]) AddListener(fn func(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, value, old V, reason EventReason)) {
+	m.cache.AddListener(func(e Event[args6[K1, K2, K3, K4, K5, K6], V]) {
+		fn(e.Key.k1, e.Key.k2, e.Key.k3, e.Key.k4, e.Key.k5, e.Key.k6, e.Value, e.Old, e.Reason)
+	})
+}
+
+// Close stops the background work of the cache, see Cache.Close.
+func (m *Memoizer6[K1, K2, K3, K4, K5, K6, V]) Close() {
+	m.cache.Close()
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (m *Memoizer6[K1, K2, K3, K4, K5, K6, V]) Stats() Stats {
+	return m.cache.Stats()
+}
+
+// ResetStats sets the counters of the cache to zero.
+func (m *Memoizer6[K1, K2, K3, K4, K5, K6, V]) ResetStats() {
+	m.cache.ResetStats()
+}
+
+// Memoizer7 is a memoized function with seven keys, along with control over its cache.
+type Memoizer7[K1, K2, K3, K4, K5, K6, K7 comparable, V any] struct {
+	cache     *Cache[args7[K1, K2, K3, K4, K5, K6, K7], V]
+	computeFn func(K1, K2, K3, K4, K5, K6, K7) V
+	hasher    Hasher
+}
+
+// NewMemoizer7 returns a Memoizer7 memoizing the compute function with seven keys and a specified TTL.
+// K1, K2, K3, K4, K5, K6, and K7 are the types of the keys, and V is the type of the value returned by the compute function.
+func NewMemoizer7[K1, K2, K3, K4, K5, K6, K7 comparable, V any](computeFn func(K1, K2, K3, K4, K5, K6, K7) V, ttl time.Duration, opts ...Option) *Memoizer7[K1, K2, K3, K4, K5, K6, K7, V] {
+	cache := newCache[args7[K1, K2, K3, K4, K5, K6, K7], V](0, ttl, hashOf[args7[K1, K2, K3, K4, K5, K6, K7]], opts...)
+	return &Memoizer7[K1, K2, K3, K4, K5, K6, K7, V]{cache: cache, computeFn: computeFn, hasher: cache.keyHasher()}
+}
+
+// key returns the cache key of the arguments.
+func (m *Memoizer7[K1, K2, K3, K4, K5, K6, K7, V]) key(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, key7 K7) args7[K1, K2, K3, K4, K5, K6, K7] {
+	return newArgs7(m.hasher, key1, key2, key3, key4, key5, key6, key7)
+}
+
+// Call returns the memoized result for the given keys, computing it if not present or expired.
+func (m *Memoizer7[K1, K2, K3, K4, K5, K6, K7, V]) Call(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, key7 K7) V {
+	key := m.key(key1, key2, key3, key4, key5, key6, key7)
+	if e := m.cache.lookup(key); e != nil {
+		return e.value
+	}
+	computeFn := m.computeFn
+	return m.cache.GetOrCompute(key, func() V {
+		return computeFn(key1, key2, key3, key4, key5, key6, key7)
+	})
+}
+
+// Invalidate removes the cached result for the given keys, so that the next call computes it again.
+func (m *Memoizer7[K1, K2, K3, K4, K5, K6, K7, V]) Invalidate(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, key7 K7) {
+	m.cache.Delete(m.key(key1, key2, key3, key4, key5, key6, key7))
+}
+
+// Purge removes all cached results.
+func (m *Memoizer7[K1, K2, K3, K4, K5, K6, K7, V]) Purge() {
+	m.cache.Purge()
+}
+
+// Len returns the number of cached results, including expired ones the janitor has not removed yet.
+func (m *Memoizer7[K1, K2, K3, K4, K5, K6, K7, V]) Len() int {
+	return m.cache.Len()
+}
+
+// Peek returns the cached result for the given keys if present and not expired, without computing it or counting as a use.
+func (m *Memoizer7[K1, K2, K3, K4, K5, K6, K7, V]) Peek(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, key7 K7) (V, bool) {
+	return m.cache.peek(m.key(key1, key2, key3, key4, key5, key6, key7))
+}
+
+// Set caches value as the result for the given keys.
+func (m *Memoizer7[K1, K2, K3, K4, K5, K6, K7, V]) Set(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, key7 K7, value V) {
+	m.cache.Set(m.key(key1, key2, key3, key4, key5, key6, key7), value)
+}
+
+// AddListener registers fn to be called when a result is cached, replaced or removed, with its keys, see Cache.AddListener.
+func (m *Memoizer7[K1, K2, K3, K4, K5, K6, K7, V]) AddListener(fn func(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, key7 K7, value, old V, reason EventReason)) {
+	m.cache.AddListener(func(e Event[args7[K1, K2, K3, K4, K5, K6, K7], V]) {
+		fn(e.Key.k1, e.Key.k2, e.Key.k3, e.Key.k4, e.Key.k5, e.Key.k6, e.Key.k7, e.Value, e.Old, e.Reason)
+	})
+}
+
+// Close stops the background work of the cache, see Cache.Close.
+func (m *Memoizer7[K1, K2, K3, K4, K5, K6, K7, V]) Close() {
+	m.cache.Close()
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (m *Memoizer7[K1, K2, K3, K4, K5, K6, K7, V]) Stats() Stats {
+	return m.cache.Stats()
+}
+
+// ResetStats sets the counters of the cache to zero.
+func (m *Memoizer7[K1, K2, K3, K4, K5, K6, K7, V]) ResetStats() {
+	m.cache.ResetStats()
+}
@@ -0,0 +1,641 @@
+package go_memoize
+
+import (
+	"context"
+	"time"
+)
+
+// MemoizerCtx is a memoized function without arguments, along with control over its cache.
+type MemoizerCtx[V any] struct {
+	cache     *Cache[uint64, V]
+	computeFn func(context.Context) V
+}
+
+// NewMemoizerCtx returns a MemoizerCtx memoizing the compute function with a specified TTL.
+// V is the type of the value returned by the compute function.
+func NewMemoizerCtx[V any](computeFn func(context.Context) V, ttl time.Duration, opts ...Option) *MemoizerCtx[V] {
+	cache := newCache[uint64, V](1, ttl, nil, opts...)
+	return &MemoizerCtx[V]{cache: cache, computeFn: computeFn}
+}
+
+// Call returns the memoized result, computing it if not present or expired.
+func (m *MemoizerCtx[V]) Call(ctx context.Context) V {
+	if e := m.cache.lookup(0); e != nil {
+		return e.value
+	}
+	// the compute function may be kept by a refresh-ahead entry: capturing m would keep the cache from being collected
+	computeFn := m.computeFn
+	value, _ := m.cache.getOrCompute(0, func() (V, error) {
+		return computeFn(ctx), nil
+	}, func() (V, error) {
+		return computeFn(context.WithoutCancel(ctx)), nil
+	})
+	return value
+}
+
+// Invalidate removes the cached result, so that the next call computes it again.
+func (m *MemoizerCtx[V]) Invalidate() {
+	m.cache.Delete(0)
+}
+
+// Purge removes all cached results.
+func (m *MemoizerCtx[V]) Purge() {
+	m.cache.Purge()
+}
+
+// Len returns the number of cached results, including expired ones the janitor has not removed yet.
+func (m *MemoizerCtx[V]) Len() int {
+	return m.cache.Len()
+}
+
+// Peek returns the cached result if present and not expired, without computing it or counting as a use.
+func (m *MemoizerCtx[V]) Peek() (V, bool) {
+	return m.cache.peek(0)
+}
+
+// Set caches value as the result.
+func (m *MemoizerCtx[V]) Set(value V) {
+	m.cache.Set(0, value)
+}
+
+// AddListener registers fn to be called when the result is cached, replaced or removed, see Cache.AddListener.
+func (m *MemoizerCtx[V]) AddListener(fn func(value, old V, reason EventReason)) {
+	m.cache.AddListener(func(e Event[uint64, V]) {
+		fn(e.Value, e.Old, e.Reason)
+	})
+}
+
+// Close stops the background work of the cache, see Cache.Close.
+func (m *MemoizerCtx[V]) Close() {
+	m.cache.Close()
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (m *MemoizerCtx[V]) Stats() Stats {
+	return m.cache.Stats()
+}
+
+// ResetStats sets the counters of the cache to zero.
+func (m *MemoizerCtx[V]) ResetStats() {
+	m.cache.ResetStats()
+}
+
+// MemoizerCtx1 is a memoized function with a single key, along with control over its cache.
+type MemoizerCtx1[K comparable, V any] struct {
+	cache     *Cache[K, V]
+	computeFn func(context.Context, K) V
+}
+
+// NewMemoizerCtx1 returns a MemoizerCtx1 memoizing the compute function with a single key and a specified TTL.
+// K is the type of the key, and V is the type of the value returned by the compute function.
+func NewMemoizerCtx1[K comparable, V any](computeFn func(context.Context, K) V, ttl time.Duration, opts ...Option) *MemoizerCtx1[K, V] {
+	cache := newCache[K, V](0, ttl, nil, opts...)
+	return &MemoizerCtx1[K, V]{cache: cache, computeFn: computeFn}
+}
+
+// Call returns the memoized result for the given key, computing it if not present or expired.
+func (m *MemoizerCtx1[K, V]) Call(ctx context.Context, k K) V {
+	if e := m.cache.lookup(k); e != nil {
+		return e.value
+	}
+	computeFn := m.computeFn
+	value, _ := m.cache.getOrCompute(k, func() (V, error) {
+		return computeFn(ctx, k), nil
+	}, func() (V, error) {
+		return computeFn(context.WithoutCancel(ctx), k), nil
+	})
+	return value
+}
+
+// Invalidate removes the cached result for the given key, so that the next call computes it again.
+func (m *MemoizerCtx1[K, V]) Invalidate(k K) {
+	m.cache.Delete(k)
+}
+
+// Purge removes all cached results.
+func (m *MemoizerCtx1[K, V]) Purge() {
+	m.cache.Purge()
+}
+
+// Len returns the number of cached results, including expired ones the janitor has not removed yet.
+func (m *MemoizerCtx1[K, V]) Len() int {
+	return m.cache.Len()
+}
+
+// Peek returns the cached result for the given key if present and not expired, without computing it or counting as a use.
+func (m *MemoizerCtx1[K, V]) Peek(k K) (V, bool) {
+	return m.cache.peek(k)
+}
+
+// Set caches value as the result for the given key.
+func (m *MemoizerCtx1[K, V]) Set(k K, value V) {
+	m.cache.Set(k, value)
+}
+
+// AddListener registers fn to be called when a result is cached, replaced or removed, with its key, see Cache.AddListener.
+func (m *MemoizerCtx1[K, V]) AddListener(fn func(k K, value, old V, reason EventReason)) {
+	m.cache.AddListener(func(e Event[K, V]) {
+		fn(e.Key, e.Value, e.Old, e.Reason)
+	})
+}
+
+// Close stops the background work of the cache, see Cache.Close.
+func (m *MemoizerCtx1[K, V]) Close() {
+	m.cache.Close()
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (m *MemoizerCtx1[K, V]) Stats() Stats {
+	return m.cache.Stats()
+}
+
+// ResetStats sets the counters of the cache to zero.
+func (m *MemoizerCtx1[K, V]) ResetStats() {
+	m.cache.ResetStats()
+}
+
+// MemoizerCtx2 is a memoized function with two keys, along with control over its cache.
+type MemoizerCtx2[K1, K2 comparable, V any] struct {
+	cache     *Cache[args2[K1, K2], V]
+	computeFn func(context.Context, K1, K2) V
+	hasher    Hasher
+}
+
+// NewMemoizerCtx2 returns a MemoizerCtx2 memoizing the compute function with two keys and a specified TTL.
+// K1 and K2 are the types of the keys, and V is the type of the value returned by the compute function.
+func NewMemoizerCtx2[K1, K2 comparable, V any](computeFn func(context.Context, K1, K2) V, ttl time.Duration, opts ...Option) *MemoizerCtx2[K1, K2, V] {
+	cache := newCache[args2[K1, K2], V](0, ttl, hashOf[args2[K1, K2]], opts...)
+	return &MemoizerCtx2[K1, K2, V]{cache: cache, computeFn: computeFn, hasher: cache.keyHasher()}
+}
+
+// key returns the cache key of the arguments.
+func (m *MemoizerCtx2[K1, K2, V]) key(key1 K1, key2 K2) args2[K1, K2] {
+	return newArgs2(m.hasher, key1, key2)
+}
+
+// Call returns the memoized result for the given keys, computing it if not present or expired.
+func (m *MemoizerCtx2[K1, K2, V]) Call(ctx context.Context, key1 K1, key2 K2) V {
+	key := m.key(key1, key2)
+	if e := m.cache.lookup(key); e != nil {
+		return e.value
+	}
+	computeFn := m.computeFn
+	value, _ := m.cache.getOrCompute(key, func() (V, error) {
+		return computeFn(ctx, key1, key2), nil
+	}, func() (V, error) {
+		return computeFn(context.WithoutCancel(ctx), key1, key2), nil
+	})
+	return value
+}
+
+// Invalidate removes the cached result for the given keys, so that the next call computes it again.
+func (m *MemoizerCtx2[K1, K2, V]) Invalidate(key1 K1, key2 K2) {
+	m.cache.Delete(m.key(key1, key2))
+}
+
+// Purge removes all cached results.
+func (m *MemoizerCtx2[K1, K2, V]) Purge() {
+	m.cache.Purge()
+}
+
+// Len returns the number of cached results, including expired ones the janitor has not removed yet.
+func (m *MemoizerCtx2[K1, K2, V]) Len() int {
+	return m.cache.Len()
+}
+
+// Peek returns the cached result for the given keys if present and not expired, without computing it or counting as a use.
+func (m *MemoizerCtx2[K1, K2, V]) Peek(key1 K1, key2 K2) (V, bool) {
+	return m.cache.peek(m.key(key1, key2))
+}
+
+// Set caches value as the result for the given keys.
+func (m *MemoizerCtx2[K1, K2, V]) Set(key1 K1, key2 K2, value V) {
+	m.cache.Set(m.key(key1, key2), value)
+}
+
+// AddListener registers fn to be called when a result is cached, replaced or removed, with its keys, see Cache.AddListener.
+func (m *MemoizerCtx2[K1, K2, V]) AddListener(fn func(key1 K1, key2 K2, value, old V, reason EventReason)) {
+	m.cache.AddListener(func(e Event[args2[K1, K2], V]) {
+		fn(e.Key.k1, e.Key.k2, e.Value, e.Old, e.Reason)
+	})
+}
+
+// Close stops the background work of the cache, see Cache.Close.
+func (m *MemoizerCtx2[K1, K2, V]) Close() {
+	m.cache.Close()
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (m *MemoizerCtx2[K1, K2, V]) Stats() Stats {
+	return m.cache.Stats()
+}
+
+// ResetStats sets the counters of the cache to zero.
+func (m *MemoizerCtx2[K1, K2, V]) ResetStats() {
+	m.cache.ResetStats()
+}
+
+// MemoizerCtx3 is a memoized function with three keys, along with control over its cache.
+type MemoizerCtx3[K1, K2, K3 comparable, V any] struct {
+	cache     *Cache[args3[K1, K2, K3], V]
+	computeFn func(context.Context, K1, K2, K3) V
+	hasher    Hasher
+}
+
+// NewMemoizerCtx3 returns a MemoizerCtx3 memoizing the compute function with three keys and a specified TTL.
+// K1, K2, and K3 are the types of the keys, and V is the type of the value returned by the compute function.
+func NewMemoizerCtx3[K1, K2, K3 comparable, V any](computeFn func(context.Context, K1, K2, K3) V, ttl time.Duration, opts ...Option) *MemoizerCtx3[K1, K2, K3, V] {
+	cache := newCache[args3[K1, K2, K3], V](0, ttl, hashOf[args3[K1, K2, K3]], opts...)
+	return &MemoizerCtx3[K1, K2, K3, V]{cache: cache, computeFn: computeFn, hasher: cache.keyHasher()}
+}
+
+// key returns the cache key of the arguments.
+func (m *MemoizerCtx3[K1, K2, K3, V]) key(key1 K1, key2 K2, key3 K3) args3[K1, K2, K3] {
+	return newArgs3(m.hasher, key1, key2, key3)
+}
+
+// Call returns the memoized result for the given keys, computing it if not present or expired.
+func (m *MemoizerCtx3[K1, K2, K3, V]) Call(ctx context.Context, key1 K1, key2 K2, key3 K3) V {
+	key := m.key(key1, key2, key3)
+	if e := m.cache.lookup(key); e != nil {
+		return e.value
+	}
+	computeFn := m.computeFn
+	value, _ := m.cache.getOrCompute(key, func() (V, error) {
+		return computeFn(ctx, key1, key2, key3), nil
+	}, func() (V, error) {
+		return computeFn(context.WithoutCancel(ctx), key1, key2, key3), nil
+	})
+	return value
+}
+
+// Invalidate removes the cached result for the given keys, so that the next call computes it again.
+func (m *MemoizerCtx3[K1, K2, K3, V]) Invalidate(key1 K1, key2 K2, key3 K3) {
+	m.cache.Delete(m.key(key1, key2, key3))
+}
+
+// Purge removes all cached results.
+func (m *MemoizerCtx3[K1, K2, K3, V]) Purge() {
+	m.cache.Purge()
+}
+
+// Len returns the number of cached results, including expired ones the janitor has not removed yet.
+func (m *MemoizerCtx3[K1, K2, K3, V]) Len() int {
+	return m.cache.Len()
+}
+
+// Peek returns the cached result for the given keys if present and not expired, without computing it or counting as a use.
+func (m *MemoizerCtx3[K1, K2, K3, V]) Peek(key1 K1, key2 K2, key3 K3) (V, bool) {
+	return m.cache.peek(m.key(key1, key2, key3))
+}
+
+// Set caches value as the result for the given keys.
+func (m *MemoizerCtx3[K1, K2, K3, V]) Set(key1 K1, key2 K2, key3 K3, value V) {
+	m.cache.Set(m.key(key1, key2, key3), value)
+}
+
+// AddListener registers fn to be called when a result is cached, replaced or removed, with its keys, see Cache.AddListener.
+func (m *MemoizerCtx3[K1, K2, K3, V]) AddListener(fn func(key1 K1, key2 K2, key3 K3, value, old V, reason EventReason)) {
+	m.cache.AddListener(func(e Event[args3[K1, K2, K3], V]) {
+		fn(e.Key.k1, e.Key.k2, e.Key.k3, e.Value, e.Old, e.Reason)
+	})
+}
+
+// Close stops the background work of the cache, see Cache.Close.
+func (m *MemoizerCtx3[K1, K2, K3, V]) Close() {
+	m.cache.Close()
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (m *MemoizerCtx3[K1, K2, K3, V]) Stats() Stats {
+	return m.cache.Stats()
+}
+
+// ResetStats sets the counters of the cache to zero.
+func (m *MemoizerCtx3[K1, K2, K3, V]) ResetStats() {
+	m.cache.ResetStats()
+}
+
+// MemoizerCtx4 is a memoized function with four keys, along with control over its cache.
+type MemoizerCtx4[K1, K2, K3, K4 comparable, V any] struct {
+	cache     *Cache[args4[K1, K2, K3, K4], V]
+	computeFn func(context.Context, K1, K2, K3, K4) V
+	hasher    Hasher
+}
+
+// NewMemoizerCtx4 returns a MemoizerCtx4 memoizing the compute function with four keys and a specified TTL.
+// K1, K2, K3, and K4 are the types of the keys, and V is the type of the value returned by the compute function.
+func NewMemoizerCtx4[K1, K2, K3, K4 comparable, V any](computeFn func(context.Context, K1, K2, K3, K4) V, ttl time.Duration, opts ...Option) *MemoizerCtx4[K1, K2, K3, K4, V] {
+	cache := newCache[args4[K1, K2, K3, K4], V](0, ttl, hashOf[args4[K1, K2, K3, K4]], opts...)
+	return &MemoizerCtx4[K1, K2, K3, K4, V]{cache: cache, computeFn: computeFn, hasher: cache.keyHasher()}
+}
+
+// key returns the cache key of the arguments.
+func (m *MemoizerCtx4[K1, K2, K3, K4, V]) key(key1 K1, key2 K2, key3 K3, key4 K4) args4[K1, K2, K3, K4] {
+	return newArgs4(m.hasher, key1, key2, key3, key4)
+}
+
+// Call returns the memoized result for the given keys, computing it if not present or expired.
+func (m *MemoizerCtx4[K1, K2, K3, K4, V]) Call(ctx context.Context, key1 K1, key2 K2, key3 K3, key4 K4) V {
+	key := m.key(key1, key2, key3, key4)
+	if e := m.cache.lookup(key); e != nil {
+		return e.value
+	}
+	computeFn := m.computeFn
+	value, _ := m.cache.getOrCompute(key, func() (V, error) {
+		return computeFn(ctx, key1, key2, key3, key4), nil
+	}, func() (V, error) {
+		return computeFn(context.WithoutCancel(ctx), key1, key2, key3, key4), nil
+	})
+	return value
+}
+
+// Invalidate removes the cached result for the given keys, so that the next call computes it again.
+func (m *MemoizerCtx4[K1, K2, K3, K4, V]) Invalidate(key1 K1, key2 K2, key3 K3, key4 K4) {
+	m.cache.Delete(m.key(key1, key2, key3, key4))
+}
+
+// Purge removes all cached results.
+func (m *MemoizerCtx4[K1, K2, K3, K4, V]) Purge() {
+	m.cache.Purge()
+}
+
+// Len returns the number of cached results, including expired ones the janitor has not removed yet.
+func (m *MemoizerCtx4[K1, K2, K3, K4, V]) Len() int {
+	return m.cache.Len()
+}
+
+// Peek returns the cached result for the given keys if present and not expired, without computing it or counting as a use.
+func (m *MemoizerCtx4[K1, K2, K3, K4, V]) Peek(key1 K1, key2 K2, key3 K3, key4 K4) (V, bool) {
+	return m.cache.peek(m.key(key1, key2, key3, key4))
+}
+
+// Set caches value as the result for the given keys.
+func (m *MemoizerCtx4[K1, K2, K3, K4, V]) Set(key1 K1, key2 K2, key3 K3, key4 K4, value V) {
+	m.cache.Set(m.key(key1, key2, key3, key4), value)
+}
+
+// AddListener registers fn to be called when a result is cached, replaced or removed, with its keys, see Cache.AddListener.
+func (m *MemoizerCtx4[K1, K2, K3, K4, V]) AddListener(fn func(key1 K1, key2 K2, key3 K3, key4 K4, value, old V, reason EventReason)) {
+	m.cache.AddListener(func(e Event[args4[K1, K2, K3, K4], V]) {
+		fn(e.Key.k1, e.Key.k2, e.Key.k3, e.Key.k4, e.Value, e.Old, e.Reason)
+	})
+}
+
+// Close stops the background work of the cache, see Cache.Close.
+func (m *MemoizerCtx4[K1, K2, K3, K4, V]) Close() {
+	m.cache.Close()
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (m *MemoizerCtx4[K1, K2, K3, K4, V]) Stats() Stats {
+	return m.cache.Stats()
+}
+
+// ResetStats sets the counters of the cache to zero.
+func (m *MemoizerCtx4[K1, K2, K3, K4, V]) ResetStats() {
+	m.cache.ResetStats()
+}
+
+// MemoizerCtx5 is a memoized function with five keys, along with control over its cache.
+type MemoizerCtx5[K1, K2, K3, K4, K5 comparable, V any] struct {
+	cache     *Cache[args5[K1, K2, K3, K4, K5], V]
+	computeFn func(context.Context, K1, K2, K3, K4, K5) V
+	hasher    Hasher
+}
+
+// NewMemoizerCtx5 returns a MemoizerCtx5 memoizing the compute function with five keys and a specified TTL.
+// K1, K2, K3, K4, and K5 are the types of the keys, and V is the type of the value returned by the compute function.
+func NewMemoizerCtx5[K1, K2, K3, K4, K5 comparable, V any](computeFn func(context.Context, K1, K2, K3, K4, K5) V, ttl time.Duration, opts ...Option) *MemoizerCtx5[K1, K2, K3, K4, K5, V] {
+	cache := newCache[args5[K1, K2, K3, K4, K5], V](0, ttl, hashOf[args5[K1, K2, K3, K4, K5]], opts...)
+	return &MemoizerCtx5[K1, K2, K3, K4, K5, V]{cache: cache, computeFn: computeFn, hasher: cache.keyHasher()}
+}
+
+// key returns the cache key of the arguments.
+func (m *MemoizerCtx5[K1, K2, K3, K4, K5, V]) key(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5) args5[K1, K2, K3, K4, K5] {
+	return newArgs5(m.hasher, key1, key2, key3, key4, key5)
+}
+
+// Call returns the memoized result for the given keys, computing it if not present or expired.
+func (m *MemoizerCtx5[K1, K2, K3, K4, K5, V]) Call(ctx context.Context, key1 K1, key2 K2, key3 K3, key4 K4, key5 K5) V {
+	key := m.key(key1, key2, key3, key4, key5)
+	if e := m.cache.lookup(key); e != nil {
+		return e.value
+	}
+	computeFn := m.computeFn
+	value, _ := m.cache.getOrCompute(key, func() (V, error) {
+		return computeFn(ctx, key1, key2, key3, key4, key5), nil
+	}, func() (V, error) {
+		return computeFn(context.WithoutCancel(ctx), key1, key2, key3, key4, key5), nil
+	})
+	return value
+}
+
+// Invalidate removes the cached result for the given keys, so that the next call computes it again.
+func (m *MemoizerCtx5[K1, K2, K3, K4, K5, V]) Invalidate(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5) {
+	m.cache.Delete(m.key(key1, key2, key3, key4, key5))
+}
+
+// Purge removes all cached results.
+func (m *MemoizerCtx5[K1, K2, K3, K4, K5, V]) Purge() {
+	m.cache.Purge()
+}
+
+// Len returns the number of cached results, including expired ones the janitor has not removed yet.
+func (m *MemoizerCtx5[K1, K2, K3, K4, K5, V]) Len() int {
+	return m.cache.Len()
+}
+
+// Peek returns the cached result for the given keys if present and not expired, without computing it or counting as a use.
+func (m *MemoizerCtx5[K1, K2, K3, K4, K5, V]) Peek(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5) (V, bool) {
+	return m.cache.peek(m.key(key1, key2, key3, key4, key5))
+}
+
+// Set caches value as the result for the given keys.
+func (m *MemoizerCtx5[K1, K2, K3, K4, K5, V]) Set(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, value V) {
+	m.cache.Set(m.key(key1, key2, key3, key4, key5), value)
+}
+
+// AddListener registers fn to be called when a result is cached, replaced or removed, with its keys, see Cache.AddListener.
+func (m *MemoizerCtx5[K1, K2, K3, K4, K5, V]) AddListener(fn func(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, value, old V, reason EventReason)) {
+	m.cache.AddListener(func(e Event[args5[K1, K2, K3, K4, K5], V]) {
+		fn(e.Key.k1, e.Key.k2, e.Key.k3, e.Key.k4, e.Key.k5, e.Value, e.Old, e.Reason)
+	})
+}
+
+// Close stops the background work of the cache, see Cache.Close.
+func (m *MemoizerCtx5[K1, K2, K3, K4, K5, V]) Close() {
+	m.cache.Close()
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (m *MemoizerCtx5[K1, K2, K3, K4, K5, V]) Stats() Stats {
+	return m.cache.Stats()
+}
+
+// ResetStats sets the counters of the cache to zero.
+func (m *MemoizerCtx5[K1, K2, K3, K4, K5, V]) ResetStats() {
+	m.cache.ResetStats()
+}
+
+// MemoizerCtx6 is a memoized function with six keys, along with control over its cache.
+type MemoizerCtx6[K1, K2, K3, K4, K5, K6 comparable, V any] struct {
+	cache     *Cache[args6[K1, K2, K3, K4, K5, K6], V]
+	computeFn func(context.Context, K1, K2, K3, K4, K5, K6) V
+	hasher    Hasher
+}
+
+// NewMemoizerCtx6 returns a MemoizerCtx6 memoizing the compute function with six keys and a specified TTL.
+// K1, K2, K3, K4, K5, and K6 are the types of the keys, and V is the type of the value returned by the compute function.
+func NewMemoizerCtx6[K1, K2, K3, K4, K5, K6 comparable, V any](computeFn func(context.Context, K1, K2, K3, K4, K5, K6) V, ttl time.Duration, opts ...Option) *MemoizerCtx6[K1, K2, K3, K4, K5, K6, V] {
+	cache := newCache[args6[K1, K2, K3, K4, K5, K6], V](0, ttl, hashOf[args6[K1, K2, K3, K4, K5, K6]], opts...)
+	return &MemoizerCtx6[K1, K2, K3, K4, K5, K6, V]{cache: cache, computeFn: computeFn, hasher: cache.keyHasher()}
+}
+
+// key returns the cache key of the arguments.
+func (m *MemoizerCtx6[K1, K2, K3, K4, K5, K6, V]) key(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6) args6[K1, K2, K3, K4, K5, K6] {
+	return newArgs6(m.hasher, key1, key2, key3, key4, key5, key6)
+}
+
+// Call returns the memoized result for the given keys, computing it if not present or expired.
+func (m *MemoizerCtx6[K1, K2, K3, K4, K5, K6, V]) Call(ctx context.Context, key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6) V {
+	key := m.key(key1, key2, key3, key4, key5, key6)
+	if e := m.cache.lookup(key); e != nil {
+		return e.value
+	}
+	computeFn := m.computeFn
+	value, _ := m.cache.getOrCompute(key, func() (V, error) {
+		return computeFn(ctx, key1, key2, key3, key4, key5, key6), nil
+	}, func() (V, error) {
+		return computeFn(context.WithoutCancel(ctx), key1, key2, key3, key4, key5, key6), nil
+	})
+	return value
+}
+
+// Invalidate removes the cached result for the given keys, so that the next call computes it again.
+func (m *MemoizerCtx6[K1, K2, K3, K4, K5, K6, V]) Invalidate(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6) {
+	m.cache.Delete(m.key(key1, key2, key3, key4, key5, key6))
+}
+
+// Purge removes all cached results.
+func (m *MemoizerCtx6[K1, K2, K3, K4, K5, K6, V]) Purge() {
+	m.cache.Purge()
+}
+
+// Len returns the number of cached results, including expired ones the janitor has not removed yet.
+func (m *MemoizerCtx6[K1, K2, K3, K4, K5, K6, V]) Len() int {
+	return m.cache.Len()
+}
+
+// Peek returns the cached result for the given keys if present and not expired, without computing it or counting as a use.
+func (m *MemoizerCtx6[K1, K2, K3, K4, K5, K6, V]) Peek(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6) (V, bool) {
+	return m.cache.peek(m.key(key1, key2, key3, key4, key5, key6))
+}
+
+// Set caches value as the result for the given keys.
+func (m *MemoizerCtx6[K1, K2, K3, K4, K5, K6, V]) Set(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, value V) {
+	m.cache.Set(m.key(key1, key2, key3, key4, key5, key6), value)
+}
+
+// AddListener registers fn to be called when a result is cached, replaced or removed, with its keys, see Cache.AddListener.
+func (m *MemoizerCtx6[K1, K2, K3, K4, K5, K6, V]) AddListener(fn func(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, value, old V, reason EventReason)) {
+	m.cache.AddListener(func(e Event[args6[K1, K2, K3, K4, K5, K6], V]) {
+		fn(e.Key.k1, e.Key.k2, e.Key.k3, e.Key.k4, e.Key.k5, e.Key.k6, e.Value, e.Old, e.Reason)
+	})
+}
+
+// Close stops the background work of the cache, see Cache.Close.
+func (m *MemoizerCtx6[K1, K2, K3, K4, K5, K6, V]) Close() {
+	m.cache.Close()
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (m *MemoizerCtx6[K1, K2, K3, K4, K5, K6, V]) Stats() Stats {
+	return m.cache.Stats()
+}
+
+// ResetStats sets the counters of the cache to zero.
+func (m *MemoizerCtx6[K1, K2, K3, K4, K5, K6, V]) ResetStats() {
+	m.cache.ResetStats()
+}
+
+// MemoizerCtx7 is a memoized function with seven keys, along with control over its cache.
+type MemoizerCtx7[K1, K2, K3, K4, K5, K6, K7 comparable, V any] struct {
+	cache     *Cache[args7[K1, K2, K3, K4, K5, K6, K7], V]
+	computeFn func(context.Context, K1, K2, K3, K4, K5, K6, K7) V
+	hasher    Hasher
+}
+
+// NewMemoizerCtx7 returns a MemoizerCtx7 memoizing the compute function with seven keys and a specified TTL.
+// K1, K2, K3, K4, K5, K6, and K7 are the types of the keys, and V is the type of the value returned by the compute function.
+func NewMemoizerCtx7[K1, K2, K3, K4, K5, K6, K7 comparable, V any](computeFn func(context.Context, K1, K2, K3, K4, K5, K6, K7) V, ttl time.Duration, opts ...Option) *MemoizerCtx7[K1, K2, K3, K4, K5, K6, K7, V] {
+	cache := newCache[args7[K1, K2, K3, K4, K5, K6, K7], V](0, ttl, hashOf[args7[K1, K2, K3, K4, K5, K6, K7]], opts...)
+	return &MemoizerCtx7[K1, K2, K3, K4, K5, K6, K7, V]{cache: cache, computeFn: computeFn, hasher: cache.keyHasher()}
+}
+
+// key returns the cache key of the arguments.
+func (m *MemoizerCtx7[K1, K2, K3, K4, K5, K6, K7, V]) key(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, key7 K7) args7[K1, K2, K3, K4, K5, K6, K7] {
+	return newArgs7(m.hasher, key1, key2, key3, key4, key5, key6, key7)
+}
+
+// Call returns the memoized result for the given keys, computing it if not present or expired.
+func (m *MemoizerCtx7[K1, K2, K3, K4, K5, K6, K7, V]) Call(ctx context.Context, key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, key7 K7) V {
+	key := m.key(key1, key2, key3, key4, key5, key6, key7)
+	if e := m.cache.lookup(key); e != nil {
+		return e.value
+	}
+	computeFn := m.computeFn
+	value, _ := m.cache.getOrCompute(key, func() (V, error) {
+		return computeFn(ctx, key1, key2, key3, key4, key5, key6, key7), nil
+	}, func() (V, error) {
+		return computeFn(context.WithoutCancel(ctx), key1, key2, key3, key4, key5, key6, key7), nil
+	})
+	return value
+}
+
+// Invalidate removes the cached result for the given keys, so that the next call computes it again.
+func (m *MemoizerCtx7[K1, K2, K3, K4, K5, K6, K7, V]) Invalidate(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, key7 K7) {
+	m.cache.Delete(m.key(key1, key2, key3, key4, key5, key6, key7))
+}
+
+// Purge removes all cached results.
+func (m *MemoizerCtx7[K1, K2, K3, K4, K5, K6, K7, V]) Purge() {
+	m.cache.Purge()
+}
+
+// Len returns the number of cached results, including expired ones the janitor has not removed yet.
+func (m *MemoizerCtx7[K1, K2, K3, K4, K5, K6, K7, V]) Len() int {
+	return m.cache.Len()
+}
+
+// Peek returns the cached result for the given keys if present and not expired, without computing it or counting as a use.
+func (m *MemoizerCtx7[K1, K2, K3, K4, K5, K6, K7, V]) Peek(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, key7 K7) (V, bool) {
+	return m.cache.peek(m.key(key1, key2, key3, key4, key5, key6, key7))
+}
+
+// Set caches value as the result for the given keys.
+func (m *MemoizerCtx7[K1, K2, K3, K4, K5, K6, K7, V]) Set(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, key7 K7, value V) {
+	m.cache.Set(m.key(key1, key2, key3, key4, key5, key6, key7), value)
+}
+
+// AddListener registers fn to be called when a result is cached, replaced or removed, with its keys, see Cache.AddListener.
+func (m *MemoizerCtx7[K1, K2, K3, K4, K5, K6, K7, V]) AddListener(fn func(key1 K1, key2 K2, key3 K3, key4 K4, key5 K5, key6 K6, key7 K7, value, old V, reason EventReason)) {
+	m.cache.AddListener(func(e Event[args7[K1, K2, K3, K4, K5, K6, K7], V]) {
+		fn(e.Key.k1, e.Key.k2, e.Key.k3, e.Key.k4, e.Key.k5, e.Key.k6, e.Key.k7, e.Value, e.Old, e.Reason)
+	})
+}
+
+// Close stops the background work of the cache, see Cache.Close.
+func (m *MemoizerCtx7[K1, K2, K3, K4, K5, K6, K7, V]) Close() {
+	m.cache.Close()
+}
+
+// Stats returns a snapshot of the counters of the cache.
+func (m *MemoizerCtx7[K1, K2, K3, K4, K5, K6, K7, V]) Stats() Stats {
+	return m.cache.Stats()
+}
+
+// ResetStats sets the counters of the cache to zero.
+func (m *MemoizerCtx7[K1, K2, K3, K4, K5, K6, K7, V]) ResetStats() {
+	m.cache.ResetStats()
+}
@@ -0,0 +1,86 @@
+package go_memoize
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoizerCtx(t *testing.T) {
+	count := 0
+	m := NewMemoizerCtx(func(ctx context.Context) int {
+		count++
+		return count
+	}, 0)
+	m.Call(context.Background())
+	m.Call(context.Background())
+	if count != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+	m.Invalidate()
+	if v := m.Call(context.Background()); v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+}
+
+func TestMemoizerCtx1(t *testing.T) {
+	count := 0
+	m := NewMemoizerCtx1(func(ctx context.Context, k string) int {
+		count++
+		return len(k)
+	}, 0)
+	m.Call(context.Background(), "abc")
+	if v, ok := m.Peek("abc"); !ok || v != 3 {
+		t.Errorf("Expected 3, got %d", v)
+	}
+	m.Set("x", 9)
+	if v := m.Call(context.Background(), "x"); v != 9 {
+		t.Errorf("Expected 9, got %d", v)
+	}
+	if m.Len() != 2 {
+		t.Errorf("Expected 2, got %d", m.Len())
+	}
+	m.Purge()
+	m.Call(context.Background(), "abc")
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+}
+
+func TestMemoizerCtx3(t *testing.T) {
+	count := 0
+	m := NewMemoizerCtx3(func(ctx context.Context, a, b, c int) int {
+		count++
+		return a + b + c
+	}, 0)
+	m.Call(context.Background(), 1, 2, 3)
+	m.Invalidate(1, 2, 3)
+	if v := m.Call(context.Background(), 1, 2, 3); v != 6 {
+		t.Errorf("Expected 6, got %d", v)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+	m.Set(1, 1, 1, 0)
+	if v, ok := m.Peek(1, 1, 1); !ok || v != 0 {
+		t.Errorf("Expected 0, got %d", v)
+	}
+}
+
+func TestMemoizerCtx1AddListener(t *testing.T) {
+	m := NewMemoizerCtx1(func(ctx context.Context, k string) int { return len(k) }, time.Minute)
+	var reasons []EventReason
+	m.AddListener(func(k string, value, old int, reason EventReason) {
+		if k != "abc" || value != 3 {
+			t.Errorf("Expected abc and 3, got %s and %d", k, value)
+		}
+		reasons = append(reasons, reason)
+	})
+
+	m.Call(context.Background(), "abc")
+	m.Purge()
+
+	if len(reasons) != 2 || reasons[0] != EventInsert || reasons[1] != EventDelete {
+		t.Errorf("Expected an insert and a delete, got %v", reasons)
+	}
+}
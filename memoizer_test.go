@@ -0,0 +1,211 @@
+package go_memoize
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoizer_InvalidateAndSet(t *testing.T) {
+	count := 0
+	m := NewMemoizer(func() int {
+		count++
+		return count
+	}, 0)
+	if v := m.Call(); v != 1 {
+		t.Errorf("Expected 1, got %d", v)
+	}
+	m.Invalidate()
+	if v := m.Call(); v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+	m.Set(42)
+	if v := m.Call(); v != 42 {
+		t.Errorf("Expected 42, got %d", v)
+	}
+	if m.Len() != 1 {
+		t.Errorf("Expected 1, got %d", m.Len())
+	}
+}
+
+func TestMemoizer1(t *testing.T) {
+	count := 0
+	m := NewMemoizer1(func(k int) int {
+		count++
+		return k * 2
+	}, 0)
+	if _, ok := m.Peek(1); ok {
+		t.Errorf("Expected no cached result before the first call")
+	}
+	m.Call(1)
+	m.Call(1)
+	m.Call(2)
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+	if v, ok := m.Peek(1); !ok || v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+	if m.Len() != 2 {
+		t.Errorf("Expected 2, got %d", m.Len())
+	}
+
+	m.Invalidate(1)
+	if _, ok := m.Peek(1); ok {
+		t.Errorf("Expected no cached result after Invalidate")
+	}
+	m.Call(1)
+	if count != 3 {
+		t.Errorf("Expected 3, got %d", count)
+	}
+
+	m.Set(3, 100)
+	if v := m.Call(3); v != 100 {
+		t.Errorf("Expected 100, got %d", v)
+	}
+	m.Purge()
+	if m.Len() != 0 {
+		t.Errorf("Expected 0, got %d", m.Len())
+	}
+	m.Call(1)
+	if count != 4 {
+		t.Errorf("Expected 4, got %d", count)
+	}
+}
+
+func TestMemoizer2(t *testing.T) {
+	count := 0
+	m := NewMemoizer2(func(a string, b int) int {
+		count++
+		return len(a) + b
+	}, 0, WithShards(4))
+	m.Call("ab", 1)
+	m.Call("ab", 1)
+	if count != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+	m.Set("x", 1, 10)
+	if v, ok := m.Peek("x", 1); !ok || v != 10 {
+		t.Errorf("Expected 10, got %d", v)
+	}
+	m.Invalidate("ab", 1)
+	m.Call("ab", 1)
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+	if m.Len() != 2 {
+		t.Errorf("Expected 2, got %d", m.Len())
+	}
+}
+
+func TestMemoizer7(t *testing.T) {
+	count := 0
+	m := NewMemoizer7(func(a, b, c, d, e, f, g int) int {
+		count++
+		return a + b + c + d + e + f + g
+	}, 0)
+	if v := m.Call(1, 2, 3, 4, 5, 6, 7); v != 28 {
+		t.Errorf("Expected 28, got %d", v)
+	}
+	m.Invalidate(1, 2, 3, 4, 5, 6, 7)
+	m.Call(1, 2, 3, 4, 5, 6, 7)
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+	m.Set(1, 1, 1, 1, 1, 1, 1, 0)
+	if v := m.Call(1, 1, 1, 1, 1, 1, 1); v != 0 {
+		t.Errorf("Expected 0, got %d", v)
+	}
+}
+
+func TestMemoizer1_PeekIgnoresExpiredResults(t *testing.T) {
+	clock := NewFakeClock()
+	m := NewMemoizer1(func(k int) int { return k }, time.Second, WithClock(clock))
+	m.Call(1)
+	clock.Advance(2 * time.Second)
+	if _, ok := m.Peek(1); ok {
+		t.Errorf("Expected expired result to be missing")
+	}
+	if m.Len() != 1 {
+		t.Errorf("Expected 1, got %d", m.Len())
+	}
+}
+
+func TestMemoizer1_PurgeWithCapacity(t *testing.T) {
+	m := NewMemoizer1(func(k int) int { return k }, 0, WithCapacity(10), WithEvictionPolicy(TinyLFU))
+	for i := 0; i < 20; i++ {
+		m.Call(i)
+	}
+	m.Purge()
+	if m.Len() != 0 || m.cache.shards[0].policy.len() != 0 {
+		t.Errorf("Expected 0, got %d entries and %d tracked", m.Len(), m.cache.shards[0].policy.len())
+	}
+	for i := 0; i < 20; i++ {
+		m.Call(i)
+	}
+	if m.Len() > 10 {
+		t.Errorf("Expected at most 10, got %d", m.Len())
+	}
+}
+
+func TestMemoizer1_PurgeDuringComputeDiscardsResult(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var count atomic.Int32
+	m := NewMemoizer1(func(k int) int {
+		if count.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+		return k
+	}, 0)
+	done := make(chan struct{})
+	go func() {
+		m.Call(1)
+		close(done)
+	}()
+	<-started
+	m.Purge()
+	close(release)
+	<-done
+	if m.Len() != 0 {
+		t.Errorf("Expected 0, got %d", m.Len())
+	}
+}
+
+func TestMemoizer2AddListener(t *testing.T) {
+	m := NewMemoizer2(func(a, b int) int { return a + b }, time.Minute)
+	type event struct {
+		a, b, value, old int
+		reason           EventReason
+	}
+	var events []event
+	m.AddListener(func(a, b, value, old int, reason EventReason) {
+		events = append(events, event{a, b, value, old, reason})
+	})
+
+	m.Call(1, 2)
+	m.Set(1, 2, 4)
+	m.Invalidate(1, 2)
+
+	expected := []event{{1, 2, 3, 0, EventInsert}, {1, 2, 4, 3, EventUpdate}, {1, 2, 4, 0, EventDelete}}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %v", len(expected), events)
+	}
+	for i, e := range expected {
+		if events[i] != e {
+			t.Errorf("Expected %+v, got %+v", e, events[i])
+		}
+	}
+}
+
+func TestMemoizerClose(t *testing.T) {
+	m := NewMemoizer1(func(k int) int { return k * 2 }, time.Minute)
+	m.Close()
+	if m.Call(2) != 4 {
+		t.Errorf("Expected 4, got %d", m.Call(2))
+	}
+	if !m.cache.closed.Load() {
+		t.Errorf("Expected the cache to be closed")
+	}
+}
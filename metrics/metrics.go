@@ -0,0 +1,150 @@
+// Package metrics exposes the statistics of memoized functions and caches in the Prometheus text exposition format,
+// without depending on the Prometheus client library.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	memoize "github.com/AhmedGoudaa/go_memoize"
+)
+
+// Source is a cache whose statistics are exported, such as a memoize.Cache or a memoizer.
+type Source interface {
+	Stats() memoize.Stats
+	Len() int
+}
+
+// Registry holds named sources and serves their metrics over HTTP.
+type Registry struct {
+	mu      sync.Mutex
+	sources map[string]Source
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{sources: make(map[string]Source)}
+}
+
+// Register adds a source exported with the given name as its cache label.
+// It returns an error if the name is already registered.
+func (r *Registry) Register(name string, source Source) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.sources[name]; ok {
+		return fmt.Errorf("metrics: cache %q is already registered", name)
+	}
+	r.sources[name] = source
+	return nil
+}
+
+// MustRegister is like Register but panics if the name is already registered.
+func (r *Registry) MustRegister(name string, source Source) {
+	if err := r.Register(name, source); err != nil {
+		panic(err)
+	}
+}
+
+// Unregister removes the source registered with the given name.
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	delete(r.sources, name)
+	r.mu.Unlock()
+}
+
+// ServeHTTP writes the metrics of all registered sources in the Prometheus text exposition format.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = r.Write(w)
+}
+
+// snapshot is the statistics of a source taken at one point in time.
+type snapshot struct {
+	name  string
+	stats memoize.Stats
+	len   int
+}
+
+// counter describes a counter metric and how to read it from the statistics.
+type counter struct {
+	name, help string
+	value      func(memoize.Stats) uint64
+}
+
+var counters = []counter{
+	{"memoize_hits_total", "Lookups served from a live entry.", func(s memoize.Stats) uint64 { return s.Hits }},
+	{"memoize_misses_total", "Lookups that found no live entry.", func(s memoize.Stats) uint64 { return s.Misses }},
+	{"memoize_stale_hits_total", "Lookups served from an expired entry while it is refreshed.", func(s memoize.Stats) uint64 { return s.StaleHits }},
+	{"memoize_computes_total", "Calls of the compute function.", func(s memoize.Stats) uint64 { return s.Computes }},
+	{"memoize_compute_errors_total", "Computations that returned an error or panicked.", func(s memoize.Stats) uint64 { return s.ComputeErrors }},
+	{"memoize_evictions_total", "Entries evicted to stay within the capacity.", func(s memoize.Stats) uint64 { return s.Evictions }},
+	{"memoize_expirations_total", "Expired entries removed by the janitor.", func(s memoize.Stats) uint64 { return s.Expirations }},
+}
+
+// Write writes the metrics of all registered sources to w in the Prometheus text exposition format.
+// Sources are written in the order of their names.
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	snapshots := make([]snapshot, 0, len(r.sources))
+	for name, source := range r.sources {
+		snapshots = append(snapshots, snapshot{name: name, stats: source.Stats(), len: source.Len()})
+	}
+	r.mu.Unlock()
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].name < snapshots[j].name })
+
+	bw := bufio.NewWriter(w)
+	for _, c := range counters {
+		writeHeader(bw, c.name, c.help, "counter")
+		for _, s := range snapshots {
+			fmt.Fprintf(bw, "%s{cache=\"%s\"} %d\n", c.name, escape(s.name), c.value(s.stats))
+		}
+	}
+
+	writeHeader(bw, "memoize_entries", "Entries in the cache, including expired entries not removed yet.", "gauge")
+	for _, s := range snapshots {
+		fmt.Fprintf(bw, "memoize_entries{cache=\"%s\"} %d\n", escape(s.name), s.len)
+	}
+	writeHeader(bw, "memoize_hit_ratio", "Fraction of lookups served from the cache.", "gauge")
+	for _, s := range snapshots {
+		fmt.Fprintf(bw, "memoize_hit_ratio{cache=\"%s\"} %s\n", escape(s.name), formatFloat(s.stats.HitRatio()))
+	}
+
+	writeHeader(bw, "memoize_compute_duration_seconds", "Duration of the computations.", "histogram")
+	for _, s := range snapshots {
+		name := escape(s.name)
+		cumulative := uint64(0)
+		for i, bound := range memoize.ComputeDurationBuckets {
+			cumulative += s.stats.ComputeDurations[i]
+			fmt.Fprintf(bw, "memoize_compute_duration_seconds_bucket{cache=\"%s\",le=\"%s\"} %d\n", name, formatFloat(bound.Seconds()), cumulative)
+		}
+		cumulative += s.stats.ComputeDurations[len(memoize.ComputeDurationBuckets)]
+		fmt.Fprintf(bw, "memoize_compute_duration_seconds_bucket{cache=\"%s\",le=\"+Inf\"} %d\n", name, cumulative)
+		fmt.Fprintf(bw, "memoize_compute_duration_seconds_sum{cache=\"%s\"} %s\n", name, formatFloat(s.stats.ComputeDuration.Seconds()))
+		fmt.Fprintf(bw, "memoize_compute_duration_seconds_count{cache=\"%s\"} %d\n", name, cumulative)
+	}
+	return bw.Flush()
+}
+
+// writeHeader writes the HELP and TYPE lines of a metric.
+func writeHeader(w io.Writer, name, help, typ string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, typ)
+}
+
+// labelEscaper escapes label values as the text exposition format requires.
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escape escapes a label value.
+func escape(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+// formatFloat formats a sample value or bucket bound.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
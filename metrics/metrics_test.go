@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	memoize "github.com/AhmedGoudaa/go_memoize"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+type fakeSource struct {
+	stats memoize.Stats
+	len   int
+}
+
+func (f fakeSource) Stats() memoize.Stats { return f.stats }
+func (f fakeSource) Len() int             { return f.len }
+
+func TestRegistryServesGoldenOutput(t *testing.T) {
+	users := fakeSource{len: 42, stats: memoize.Stats{
+		Hits: 90, Misses: 10, StaleHits: 0, Computes: 10, ComputeErrors: 1,
+		Evictions: 3, Expirations: 5, ComputeDuration: 1500 * time.Millisecond,
+	}}
+	users.stats.ComputeDurations[0] = 2
+	users.stats.ComputeDurations[2] = 5
+	users.stats.ComputeDurations[len(memoize.ComputeDurationBuckets)] = 3
+
+	registry := NewRegistry()
+	registry.MustRegister("users", users)
+	registry.MustRegister(`odd "name"\`, fakeSource{})
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+
+	if got := recorder.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Errorf("Expected the text exposition content type, got %q", got)
+	}
+	golden := "testdata/metrics.golden"
+	if *update {
+		if err := os.WriteFile(golden, recorder.Body.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(recorder.Body.Bytes(), want) {
+		t.Errorf("Expected output\n%s\ngot\n%s", want, recorder.Body.String())
+	}
+}
+
+func TestRegisterRejectsDuplicateNames(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register("users", fakeSource{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := registry.Register("users", fakeSource{}); err == nil {
+		t.Errorf("Expected an error registering a duplicate name")
+	}
+	registry.Unregister("users")
+	if err := registry.Register("users", fakeSource{}); err != nil {
+		t.Errorf("Expected no error after unregistering, got %v", err)
+	}
+}
+
+func TestRegistryExportsMemoizer(t *testing.T) {
+	square := memoize.NewMemoizer1(func(n int) int { return n * n }, time.Minute)
+	registry := NewRegistry()
+	registry.MustRegister("square", square)
+
+	square.Call(2)
+	square.Call(2)
+	square.Call(3)
+
+	var out bytes.Buffer
+	if err := registry.Write(&out); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		`memoize_hits_total{cache="square"} 1`,
+		`memoize_misses_total{cache="square"} 2`,
+		`memoize_computes_total{cache="square"} 2`,
+		`memoize_entries{cache="square"} 2`,
+		`memoize_compute_duration_seconds_count{cache="square"} 2`,
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Errorf("Expected line %q in output\n%s", line, out.String())
+		}
+	}
+}
+
+func TestWriteReturnsWriterError(t *testing.T) {
+	registry := NewRegistry()
+	registry.MustRegister("users", fakeSource{})
+	if err := registry.Write(failingWriter{}); err == nil {
+		t.Errorf("Expected the writer error")
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, io.ErrClosedPipe }
@@ -0,0 +1,140 @@
+package go_memoize
+
+import (
+	"time"
+)
+
+// Option configures a Cache or a memoized function.
+type Option func(*options)
+
+// options holds the settings applied by Option values.
+type options struct {
+	errorTTL       time.Duration
+	errorFilter    func(error) bool
+	staleTTL       time.Duration
+	refreshAhead   float64
+	refreshWorkers int
+	sliding        bool
+	maxLifetime    time.Duration
+	shards         int
+	capacity       int
+	evictionPolicy EvictionPolicy
+	clock          Clock
+	hasher         Hasher
+}
+
+// newOptions applies the given options on top of the defaults.
+func newOptions(opts []Option) options {
+	o := options{shards: 1, hasher: FNV1a}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	// every shard holds at least one entry of the capacity, see WithCapacity
+	for o.capacity > 0 && o.shards > o.capacity {
+		o.shards >>= 1
+	}
+	return o
+}
+
+// WithErrorTTL enables negative caching: errors returned by the compute function are cached for the given TTL,
+// which is independent of the TTL used for successful results. By default errors are never cached.
+// Errors wrapping context.Canceled or context.DeadlineExceeded are never cached, whatever WithErrorFilter accepts,
+// as they report the cancellation of the context of one caller rather than a result of the computation.
+func WithErrorTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.errorTTL = ttl
+	}
+}
+
+// WithErrorFilter restricts negative caching to the errors for which cacheable returns true.
+// Errors it rejects are treated as transient and are never cached. It has no effect without WithErrorTTL.
+func WithErrorFilter(cacheable func(error) bool) Option {
+	return func(o *options) {
+		o.errorFilter = cacheable
+	}
+}
+
+// WithStaleWhileRevalidate keeps serving entries for the given window after their TTL has passed.
+// The first caller to hit an expired entry within the window starts a refresh in a background goroutine,
+// and every caller gets the stale value immediately until the refresh stores the new one.
+// Past the window, callers wait for the computation as usual. Memoized functions taking a context
+// refresh with the context of the caller that triggered the refresh, without its cancellation.
+func WithStaleWhileRevalidate(window time.Duration) Option {
+	return func(o *options) {
+		o.staleTTL = window
+	}
+}
+
+// WithRefreshAhead recomputes entries in the background once they reach the given fraction of their TTL, e.g. 0.8,
+// provided they were read since they were computed, so that hot keys do not expire on the request path.
+// At most workers refreshes run at a time; due entries left over are refreshed on a later check.
+// Entries are checked when due, every sixteenth of the shortest TTL of the cache, between every millisecond and every second,
+// so a fraction above 15/16 may let entries expire before they are refreshed.
+// It has no effect on caches without a TTL or with a fraction outside (0, 1). Memoized functions taking a context
+// refresh with the context of the last caller that computed the entry, without its cancellation.
+func WithRefreshAhead(fraction float64, workers int) Option {
+	return func(o *options) {
+		if workers < 1 {
+			workers = 1
+		}
+		o.refreshAhead = fraction
+		o.refreshWorkers = workers
+	}
+}
+
+// WithSlidingExpiration makes entries expire after the TTL without access rather than the TTL after they were stored:
+// every hit extends the life of the entry, as for sessions. A maxLifetime other than zero caps the life of an entry
+// since it was stored, however often it is read. Reads extend the life of entries without locking, to within
+// a sixteenth of the TTL. Cached errors still expire after the error TTL. It has no effect on caches without a TTL.
+func WithSlidingExpiration(maxLifetime time.Duration) Option {
+	return func(o *options) {
+		o.sliding = true
+		o.maxLifetime = maxLifetime
+	}
+}
+
+// WithShards splits the cache into n independently locked shards, rounded up to a power of two,
+// to reduce lock contention on machines with many cores. The default is a single shard.
+func WithShards(n int) Option {
+	return func(o *options) {
+		shards := 1
+		for shards < n {
+			shards <<= 1
+		}
+		o.shards = shards
+	}
+}
+
+// WithCapacity bounds the cache to about maxEntries entries, evicting an entry chosen by the eviction policy when full.
+// The capacity is split between shards, which are reduced to at most maxEntries, so that their capacities add up to
+// maxEntries; as each shard evicts on its own, a cache whose keys are unevenly spread may evict before holding maxEntries.
+// By default the cache is unbounded.
+func WithCapacity(maxEntries int) Option {
+	return func(o *options) {
+		o.capacity = maxEntries
+	}
+}
+
+// WithEvictionPolicy selects the policy a cache bounded by WithCapacity uses to choose the entries to evict.
+// The default is LRU.
+func WithEvictionPolicy(p EvictionPolicy) Option {
+	return func(o *options) {
+		o.evictionPolicy = p
+	}
+}
+
+// WithClock makes the cache read the time from clock instead of the clock shared by all caches.
+// It is meant for tests, typically with a FakeClock.
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// WithHasher makes the cache hash keys with hasher instead of FNV1a.
+// Hashes are only computed by caches split into shards or bounded by a capacity.
+func WithHasher(hasher Hasher) Option {
+	return func(o *options) {
+		o.hasher = hasher
+	}
+}
@@ -0,0 +1,77 @@
+package go_memoize
+
+// EvictionPolicy selects how a bounded cache chooses the entries to evict, see WithCapacity.
+type EvictionPolicy int
+
+const (
+	// LRU evicts the least recently used entry.
+	LRU EvictionPolicy = iota
+	// TinyLFU is the W-TinyLFU policy: new entries enter a small LRU window, and leave it for the main
+	// segmented LRU region only if they are estimated to be accessed more often than the entry they would evict.
+	// It resists pollution by scans and one-off keys on skewed workloads.
+	TinyLFU
+)
+
+// evictionPolicy tracks the entries of a bounded shard and decides which one to evict.
+// All methods are called with the shard lock held.
+type evictionPolicy[K comparable, V any] interface {
+	// add starts tracking a new entry and returns the entry to evict, or nil if the shard is not over capacity.
+	// The returned entry may be e itself when the policy rejects it.
+	add(e *entry[K, V]) *entry[K, V]
+	// replace tracks e in place of old, which holds the same key.
+	replace(old, e *entry[K, V])
+	// access records a hit on a tracked entry.
+	access(e *entry[K, V])
+	// remove stops tracking e.
+	remove(e *entry[K, V])
+	// len returns the number of tracked entries.
+	len() int
+}
+
+// newEvictionPolicy creates the policy p bounded to capacity entries.
+func newEvictionPolicy[K comparable, V any](p EvictionPolicy, capacity int, keyHash func(K) uint64) evictionPolicy[K, V] {
+	switch p {
+	case TinyLFU:
+		return newTinyLFU[K, V](capacity, keyHash)
+	default:
+		return newLRU[K, V](capacity)
+	}
+}
+
+// lru is the least recently used eviction policy.
+type lru[K comparable, V any] struct {
+	capacity int
+	list     entryList[K, V]
+}
+
+// newLRU creates an LRU policy bounded to capacity entries.
+func newLRU[K comparable, V any](capacity int) *lru[K, V] {
+	p := &lru[K, V]{capacity: capacity}
+	p.list.init()
+	return p
+}
+
+func (p *lru[K, V]) add(e *entry[K, V]) *entry[K, V] {
+	p.list.pushFront(e)
+	if p.list.len > p.capacity {
+		return p.list.back()
+	}
+	return nil
+}
+
+func (p *lru[K, V]) replace(old, e *entry[K, V]) {
+	p.list.replace(old, e)
+	p.list.moveToFront(e)
+}
+
+func (p *lru[K, V]) access(e *entry[K, V]) {
+	p.list.moveToFront(e)
+}
+
+func (p *lru[K, V]) remove(e *entry[K, V]) {
+	p.list.remove(e)
+}
+
+func (p *lru[K, V]) len() int {
+	return p.list.len
+}
@@ -0,0 +1,184 @@
+package go_memoize
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// refreshState holds what refresh-ahead needs to recompute an entry in the background, see WithRefreshAhead.
+type refreshState[V any] struct {
+	at       int64 // the time in nanoseconds from which the entry is due for refresh
+	compute  computation[V]
+	accessed atomic.Bool // whether the entry was read since it was stored
+}
+
+// touch marks the entry as read for refresh-ahead.
+// The flag is only written once per entry, to keep hits on hot entries from contending on it.
+func (e *entry[K, V]) touch() {
+	if r := e.refresh; r != nil && !r.accessed.Load() {
+		r.accessed.Store(true)
+	}
+}
+
+// refreshItem is an entry queued for a refresh check at the given time.
+type refreshItem[K comparable, V any] struct {
+	at    int64
+	entry *entry[K, V]
+}
+
+// refreshQueue is a min-heap of entries ordered by the time of their next refresh check, so that the janitor finds
+// the entries due for refresh without scanning the shard. Entries replaced or removed since they were queued are
+// dropped when they come up, rather than searched for when they leave the shard.
+type refreshQueue[K comparable, V any] []refreshItem[K, V]
+
+// push queues the entry for a refresh check at the given time.
+func (q *refreshQueue[K, V]) push(at int64, e *entry[K, V]) {
+	*q = append(*q, refreshItem[K, V]{at, e})
+	h := *q
+	for i := len(h) - 1; i > 0; {
+		parent := (i - 1) / 2
+		if h[parent].at <= h[i].at {
+			break
+		}
+		h[parent], h[i] = h[i], h[parent]
+		i = parent
+	}
+}
+
+// due reports whether the queue holds an entry to check at now.
+func (q refreshQueue[K, V]) due(now int64) bool {
+	return len(q) > 0 && q[0].at <= now
+}
+
+// pop removes and returns the entry with the earliest check.
+func (q *refreshQueue[K, V]) pop() *entry[K, V] {
+	h := *q
+	e := h[0].entry
+	last := len(h) - 1
+	h[0] = h[last]
+	h[last] = refreshItem[K, V]{}
+	h = h[:last]
+	for i := 0; ; {
+		smallest, left, right := i, 2*i+1, 2*i+2
+		if left < len(h) && h[left].at < h[smallest].at {
+			smallest = left
+		}
+		if right < len(h) && h[right].at < h[smallest].at {
+			smallest = right
+		}
+		if smallest == i {
+			break
+		}
+		h[i], h[smallest] = h[smallest], h[i]
+		i = smallest
+	}
+	*q = h
+	return e
+}
+
+// reset empties the queue.
+func (q *refreshQueue[K, V]) reset() {
+	clear(*q)
+	*q = (*q)[:0]
+}
+
+// refresh starts the refreshes due and returns the interval until the next check, see sweeper.
+func (c *cache[K, V]) refresh() time.Duration {
+	interval := c.refreshInterval()
+	// the lifecycle lock makes the refreshes started here precede the wait of Close, or not start at all
+	c.lifecycle.Lock()
+	defer c.lifecycle.Unlock()
+	if !c.closed.Load() {
+		now := c.now()
+		c.refreshDue(now, now+int64(interval))
+	}
+	return interval
+}
+
+// refreshInterval returns the interval between two checks for entries due for refresh: the tick interval for the
+// shortest TTL of the cache, so that an entry is checked several times between its refresh time and its expiry
+// unless the refresh fraction is close to 1.
+func (c *cache[K, V]) refreshInterval() time.Duration {
+	ttl := c.tickTTL.Load()
+	if ttl == 0 {
+		// the cache has its own clock
+		ttl = c.ttl
+	}
+	return tickIntervalFor(ttl)
+}
+
+// refreshDue starts background refreshes of the live entries that reached their refresh time and were read since
+// they were computed, as long as a refresh slot is free. Entries left over stay queued for a later check,
+// and due entries that were not read yet are checked again at recheck, as a read before their expiry still makes them due.
+func (c *cache[K, V]) refreshDue(now, recheck int64) {
+	var batch [sweepBatchSize]*entry[K, V]
+	for i := range c.shards {
+		s := &c.shards[i]
+		for {
+			free := cap(c.refreshSlots) - len(c.refreshSlots)
+			if free == 0 {
+				return
+			}
+			due := s.dueForRefresh(now, recheck, batch[:0:min(free, len(batch))])
+			for j, e := range due {
+				select {
+				case c.refreshSlots <- struct{}{}:
+				default:
+					// the slots were taken by a concurrent check
+					s.requeue(due[j:], now)
+					return
+				}
+				c.refreshes.Add(1)
+				release := func() {
+					// a failed refresh leaves the entry in place, to be refreshed again on the next check
+					s.requeue([]*entry[K, V]{e}, recheck)
+					<-c.refreshSlots
+					c.refreshes.Done()
+				}
+				if !c.computeInBackground(s, e.key, e.refresh.compute, release) {
+					release()
+				}
+			}
+			if len(due) < cap(due) {
+				break
+			}
+		}
+	}
+}
+
+// dueForRefresh takes the entries due for refresh at now off the refresh queue of the shard, appends them to due up to its
+// capacity, and returns it. Entries replaced, removed or expired are dropped, and entries not read yet are queued again for recheck.
+func (s *shard[K, V]) dueForRefresh(now, recheck int64, due []*entry[K, V]) []*entry[K, V] {
+	s.mu.RLock()
+	ok := s.refreshQueue.due(now)
+	s.mu.RUnlock()
+	if !ok {
+		return due
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for len(due) < cap(due) && s.refreshQueue.due(now) {
+		e := s.refreshQueue.pop()
+		if s.entries[e.key] != e || !e.alive(now) {
+			continue
+		}
+		if !e.refresh.accessed.Load() {
+			s.refreshQueue.push(recheck, e)
+			continue
+		}
+		due = append(due, e)
+	}
+	return due
+}
+
+// requeue queues the entries of the shard still current for a refresh check at the given time.
+func (s *shard[K, V]) requeue(entries []*entry[K, V], at int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range entries {
+		if s.entries[e.key] == e {
+			s.refreshQueue.push(at, e)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+package go_memoize
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRefreshAhead_RefreshesReadEntries(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](10, WithClock(clock), WithRefreshAhead(0.8, 2))
+	var count atomic.Int32
+	computeFn := func() int { return int(count.Add(1)) }
+	cache.GetOrCompute(1, computeFn)
+
+	clock.Advance(5 * time.Second)
+	cache.Get(1)
+	cache.sweeper.refresh()
+	cache.refreshes.Wait()
+	if n := count.Load(); n != 1 {
+		t.Errorf("Expected 1, got %d", n)
+	}
+
+	clock.Advance(3500 * time.Millisecond)
+	cache.sweeper.refresh()
+	cache.refreshes.Wait()
+	if n := count.Load(); n != 2 {
+		t.Errorf("Expected 2, got %d", n)
+	}
+
+	// The refreshed entry outlives the TTL of the original one.
+	clock.Advance(5 * time.Second)
+	if v, ok := cache.Get(1); !ok || v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+}
+
+func TestRefreshAhead_SkipsUnreadEntries(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](10, WithClock(clock), WithRefreshAhead(0.8, 2))
+	count := 0
+	cache.GetOrCompute(1, func() int {
+		count++
+		return count
+	})
+	clock.Advance(9 * time.Second)
+	cache.sweeper.refresh()
+	cache.refreshes.Wait()
+	if count != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+}
+
+func TestRefreshAhead_BoundsConcurrentRefreshes(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](10, WithClock(clock), WithRefreshAhead(0.5, 2))
+	release := make(chan struct{})
+	var refreshing atomic.Bool
+	var started atomic.Int32
+	for i := 0; i < 10; i++ {
+		cache.GetOrCompute(i, func() int {
+			if refreshing.Load() {
+				started.Add(1)
+				<-release
+			}
+			return i
+		})
+		cache.Get(i)
+	}
+	refreshing.Store(true)
+
+	clock.Advance(6 * time.Second)
+	cache.sweeper.refresh()
+	if n := callsInFlight(cache); n != 2 {
+		t.Errorf("Expected 2, got %d", n)
+	}
+	waitFor(t, func() bool { return started.Load() == 2 })
+	cache.sweeper.refresh()
+	if n := callsInFlight(cache); n != 2 {
+		t.Errorf("Expected 2, got %d", n)
+	}
+	if n := started.Load(); n != 2 {
+		t.Errorf("Expected 2, got %d", n)
+	}
+	close(release)
+	cache.Close()
+}
+
+func TestRefreshAhead_FailedRefreshKeepsEntry(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](10, WithClock(clock), WithErrorTTL(time.Minute), WithRefreshAhead(0.8, 1))
+	calls := 0
+	computeFn := func() (int, error) {
+		calls++
+		if calls > 1 {
+			return 0, errCompute
+		}
+		return 1, nil
+	}
+	cache.GetOrComputeE(1, computeFn)
+	cache.Get(1)
+	clock.Advance(9 * time.Second)
+	cache.sweeper.refresh()
+	cache.refreshes.Wait()
+	if calls != 2 {
+		t.Errorf("Expected 2, got %d", calls)
+	}
+	if v, err := cache.GetOrComputeE(1, computeFn); v != 1 || err != nil {
+		t.Errorf("Expected 1, got %d and %v", v, err)
+	}
+}
+
+func TestRefreshAhead_IgnoredWithoutTTL(t *testing.T) {
+	cache := NewCache[int, int](0, WithRefreshAhead(0.8, 1))
+	cache.GetOrCompute(1, func() int { return 1 })
+	if e := cache.lookup(1); e.refresh != nil {
+		t.Errorf("Expected entries without TTL not to be refreshed")
+	}
+}
+
+func TestMemoizeCtx1_RefreshAheadOutlivesCanceledContext(t *testing.T) {
+	clock := NewFakeClock()
+	var count atomic.Int32
+	computeFn := func(ctx context.Context, key int) int {
+		if ctx.Err() != nil {
+			return -1
+		}
+		return int(count.Add(1))
+	}
+	m := NewMemoizerCtx1(computeFn, 10*time.Second, WithClock(clock), WithRefreshAhead(0.8, 1))
+	ctx, cancel := context.WithCancel(context.Background())
+	m.Call(ctx, 1)
+	cancel()
+	m.Call(context.Background(), 1)
+
+	clock.Advance(9 * time.Second)
+	m.cache.sweeper.refresh()
+	waitFor(t, func() bool { return m.Call(context.Background(), 1) == 2 })
+
+	clock.Advance(5 * time.Second)
+	if v := m.Call(context.Background(), 1); v != 2 {
+		t.Errorf("Expected 2, got %d", v)
+	}
+}
+
+// callsInFlight returns the number of computations in flight in the cache.
+func callsInFlight[K comparable, V any](c *Cache[K, V]) int {
+	n := 0
+	for i := range c.shards {
+		c.shards[i].mu.RLock()
+		n += len(c.shards[i].calls)
+		c.shards[i].mu.RUnlock()
+	}
+	return n
+}
+
+func TestRefreshAhead_UsesEntryTTL(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](60, WithClock(clock), WithRefreshAhead(0.5, 1))
+	cache.GetOrComputeWithTTL(1, func() (int, time.Duration, error) { return 1, 10 * time.Second, nil })
+	if at, expected := cache.lookup(1).refresh.at, clock.Now()+int64(5*time.Second); at != expected {
+		t.Errorf("Expected %d, got %d", expected, at)
+	}
+}
+
+func TestRefreshAhead_RefreshesSubSecondTTLBeforeExpiry(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCacheWithTTL[int, int](200*time.Millisecond, WithClock(clock), WithRefreshAhead(0.5, 1))
+	var count atomic.Int32
+	computeFn := func() int { return int(count.Add(1)) }
+	cache.GetOrCompute(1, computeFn)
+
+	// checked several times between the refresh time and the expiry of an entry
+	if interval := cache.sweeper.refresh(); interval > 25*time.Millisecond {
+		t.Errorf("Expected at most 25ms, got %v", interval)
+	}
+	for i := 2; i <= 3; i++ {
+		clock.Advance(110 * time.Millisecond)
+		cache.GetOrCompute(1, computeFn)
+		cache.sweeper.refresh()
+		cache.refreshes.Wait()
+		if n := count.Load(); n != int32(i) {
+			t.Errorf("Expected %d, got %d", i, n)
+		}
+	}
+	if misses := cache.Stats().Misses; misses != 1 {
+		t.Errorf("Expected 1, got %d", misses)
+	}
+}
+
+func TestRefreshAhead_RefreshesEntriesReadAfterRefreshTime(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](10, WithClock(clock), WithRefreshAhead(0.5, 1))
+	var count atomic.Int32
+	computeFn := func() int { return int(count.Add(1)) }
+	cache.GetOrCompute(1, computeFn)
+
+	clock.Advance(6 * time.Second)
+	cache.sweeper.refresh()
+	cache.refreshes.Wait()
+	if n := count.Load(); n != 1 {
+		t.Errorf("Expected 1, got %d", n)
+	}
+
+	cache.Get(1)
+	clock.Advance(time.Second)
+	cache.sweeper.refresh()
+	cache.refreshes.Wait()
+	if n := count.Load(); n != 2 {
+		t.Errorf("Expected 2, got %d", n)
+	}
+}
+
+func TestRefreshAhead_PurgeEmptiesQueue(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](10, WithClock(clock), WithRefreshAhead(0.5, 1))
+	for i := 0; i < 3; i++ {
+		cache.GetOrCompute(i, func() int { return i })
+	}
+	cache.Purge()
+	if n := len(cache.shards[0].refreshQueue); n != 0 {
+		t.Errorf("Expected 0, got %d", n)
+	}
+}
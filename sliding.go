@@ -0,0 +1,43 @@
+package go_memoize
+
+import (
+	"sync/atomic"
+)
+
+// slidingState holds the idle deadline of an entry under sliding expiration, see WithSlidingExpiration.
+type slidingState struct {
+	idle     int64        // the time in nanoseconds without access after which the entry expires
+	deadline atomic.Int64 // the time in nanoseconds at which the entry expires unless accessed
+}
+
+// slide extends the idle deadline of the entry after an access at now, without locking.
+// The deadline is only written once it moved by a fraction of the idle time, the precision of the cache clock,
+// to keep hits on hot entries from contending on it.
+func (e *entry[K, V]) slide(now int64) {
+	sl := e.sliding
+	if sl == nil {
+		return
+	}
+	deadline := sl.deadline.Load()
+	if next := now + sl.idle; next-deadline >= sl.idle/tickResolution {
+		// a failed swap means a concurrent access extended the deadline already
+		sl.deadline.CompareAndSwap(deadline, next)
+	}
+}
+
+// newEntry returns an entry storing a successful result at now, which expires after ttl or, in sliding mode,
+// after ttl without access and at the latest after the maximum lifetime of the cache. A ttl of zero means it never expires.
+func (c *cache[K, V]) newEntry(key K, value V, ttl, now int64) *entry[K, V] {
+	e := &entry[K, V]{key: key, value: value}
+	if ttl == 0 {
+		return e
+	}
+	if !c.sliding {
+		e.expireAt = now + ttl
+		return e
+	}
+	e.sliding = &slidingState{idle: ttl}
+	e.sliding.deadline.Store(now + ttl)
+	e.expireAt = expiry(now, c.maxLifetime)
+	return e
+}
@@ -0,0 +1,162 @@
+package go_memoize
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSlidingExpiration_HitsExtendLife(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](60, WithClock(clock), WithSlidingExpiration(0))
+	cache.Set(1, 1)
+
+	for i := 0; i < 5; i++ {
+		clock.Advance(50 * time.Second)
+		if _, ok := cache.Get(1); !ok {
+			t.Fatalf("Expected 1 to be cached after %d reads", i)
+		}
+	}
+	clock.Advance(61 * time.Second)
+	if _, ok := cache.Get(1); ok {
+		t.Errorf("Expected 1 to be expired after a minute without access")
+	}
+}
+
+func TestSlidingExpiration_MaxLifetime(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](60, WithClock(clock), WithSlidingExpiration(2*time.Minute))
+	cache.Set(1, 1)
+
+	clock.Advance(50 * time.Second)
+	cache.Get(1)
+	clock.Advance(50 * time.Second)
+	if _, ok := cache.Get(1); !ok {
+		t.Fatalf("Expected 1 to be cached")
+	}
+	clock.Advance(21 * time.Second)
+	if _, ok := cache.Get(1); ok {
+		t.Errorf("Expected 1 to be expired after its maximum lifetime")
+	}
+}
+
+func TestSlidingExpiration_Memoize1(t *testing.T) {
+	clock := NewFakeClock()
+	count := 0
+	memoizedFn := Memoize1(func(k int) int {
+		count++
+		return k * 2
+	}, time.Minute, WithClock(clock), WithSlidingExpiration(0))
+
+	memoizedFn(1)
+	for i := 0; i < 3; i++ {
+		clock.Advance(50 * time.Second)
+		memoizedFn(1)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1, got %d", count)
+	}
+	clock.Advance(61 * time.Second)
+	memoizedFn(1)
+	if count != 2 {
+		t.Errorf("Expected 2, got %d", count)
+	}
+}
+
+func TestSlidingExpiration_PeekDoesNotExtendLife(t *testing.T) {
+	clock := NewFakeClock()
+	m := NewMemoizer1(func(k int) int { return k }, time.Minute, WithClock(clock), WithSlidingExpiration(0))
+	m.Call(1)
+	clock.Advance(50 * time.Second)
+	m.Peek(1)
+	clock.Advance(20 * time.Second)
+	if _, ok := m.Peek(1); ok {
+		t.Errorf("Expected 1 to be expired")
+	}
+}
+
+func TestSlidingExpiration_JanitorRemovesIdleEntries(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](60, WithClock(clock), WithSlidingExpiration(0))
+	cache.Set(1, 1)
+	cache.Set(2, 2)
+
+	clock.Advance(50 * time.Second)
+	cache.Get(1)
+	clock.Advance(20 * time.Second)
+	cache.sweeper.sweep()
+	if _, ok := cache.Get(1); !ok || cacheLen(cache) != 1 {
+		t.Errorf("Expected only the idle entry to be removed, got %d entries", cacheLen(cache))
+	}
+}
+
+func TestSlidingExpiration_ErrorsExpireAfterErrorTTL(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](60, WithClock(clock), WithSlidingExpiration(0), WithErrorTTL(time.Second))
+	calls := 0
+	computeFn := func() (int, error) {
+		calls++
+		return 0, errCompute
+	}
+	cache.GetOrComputeE(1, computeFn)
+	clock.Advance(800 * time.Millisecond)
+	cache.GetOrComputeE(1, computeFn)
+	clock.Advance(800 * time.Millisecond)
+	cache.GetOrComputeE(1, computeFn)
+	if calls != 2 {
+		t.Errorf("Expected 2, got %d", calls)
+	}
+}
+
+func TestSlidingExpiration_WritesDeadlineCoarsely(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](64, WithClock(clock), WithSlidingExpiration(0))
+	cache.Set(1, 1)
+	deadline := cache.lookup(1).sliding.deadline.Load()
+
+	clock.Advance(time.Second)
+	cache.Get(1)
+	if d := cache.lookup(1).sliding.deadline.Load(); d != deadline {
+		t.Errorf("Expected the deadline not to move for a small extension, moved by %v", time.Duration(d-deadline))
+	}
+	clock.Advance(3 * time.Second)
+	cache.Get(1)
+	if d := cache.lookup(1).sliding.deadline.Load(); d != clock.Now()+int64(64*time.Second) {
+		t.Errorf("Expected the deadline to move, moved by %v", time.Duration(d-deadline))
+	}
+}
+
+func TestSlidingExpiration_StaleWhileRevalidate(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](60, WithClock(clock), WithSlidingExpiration(0), WithStaleWhileRevalidate(time.Minute))
+	cache.Set(1, 1)
+	clock.Advance(90 * time.Second)
+	if v, _ := cache.GetOrComputeE(1, func() (int, error) { return 2, nil }); v != 1 {
+		t.Errorf("Expected the stale value 1, got %d", v)
+	}
+}
+
+func TestSlidingExpiration_ConcurrentReads(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](1, WithClock(clock), WithSlidingExpiration(0), WithShards(4))
+	for i := 0; i < 16; i++ {
+		cache.Set(i, i)
+	}
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 10000; i++ {
+				if i%100 == 0 {
+					clock.Advance(100 * time.Millisecond)
+				}
+				if v, ok := cache.Get(i % 16); !ok || v != i%16 {
+					t.Errorf("Expected %d, got %d", i%16, v)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,105 @@
+package go_memoize
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ComputeDurationBuckets are the upper bounds of the buckets counting computations by duration, see Stats.ComputeDurations.
+var ComputeDurationBuckets = [...]time.Duration{
+	100 * time.Microsecond, 500 * time.Microsecond,
+	time.Millisecond, 5 * time.Millisecond, 10 * time.Millisecond, 50 * time.Millisecond, 100 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 5 * time.Second, 10 * time.Second,
+}
+
+// Stats is a snapshot of the counters of a cache.
+type Stats struct {
+	Hits            uint64        // lookups served from a live entry
+	Misses          uint64        // lookups that found no live entry, whether they computed, waited for a computation or gave up
+	StaleHits       uint64        // lookups served from an expired entry while it is refreshed, see WithStaleWhileRevalidate
+	Computes        uint64        // calls of compute functions, including background refreshes
+	ComputeErrors   uint64        // computations that returned an error or panicked
+	Evictions       uint64        // entries evicted to stay within the capacity, see WithCapacity
+	Expirations     uint64        // expired entries removed by the janitor
+	ComputeDuration time.Duration // cumulative time spent in compute functions
+
+	// ComputeDurations counts computations by duration: ComputeDurations[i] counts those that took at most
+	// ComputeDurationBuckets[i] but longer than the previous bound, and the last element counts the slower ones.
+	ComputeDurations [len(ComputeDurationBuckets) + 1]uint64
+}
+
+// HitRatio returns the fraction of lookups served from the cache, counting stale hits as hits,
+// or zero if there were no lookups.
+func (s Stats) HitRatio() float64 {
+	served := s.Hits + s.StaleHits
+	if total := served + s.Misses; total > 0 {
+		return float64(served) / float64(total)
+	}
+	return 0
+}
+
+// shardStats holds the counters of a shard. Counters are kept per shard so that sharded caches do not contend on them.
+type shardStats struct {
+	hits, misses, staleHits atomic.Uint64
+	computes, computeErrors atomic.Uint64
+	evictions, expirations  atomic.Uint64
+	computeNanos            atomic.Int64
+	computeDurations        [len(ComputeDurationBuckets) + 1]atomic.Uint64
+}
+
+// recordCompute counts a computation that took d.
+func (s *shardStats) recordCompute(d time.Duration, failed bool) {
+	s.computes.Add(1)
+	if failed {
+		s.computeErrors.Add(1)
+	}
+	s.computeNanos.Add(int64(d))
+	bucket := 0
+	for bucket < len(ComputeDurationBuckets) && d > ComputeDurationBuckets[bucket] {
+		bucket++
+	}
+	s.computeDurations[bucket].Add(1)
+}
+
+// reset sets all counters to zero.
+func (s *shardStats) reset() {
+	s.hits.Store(0)
+	s.misses.Store(0)
+	s.staleHits.Store(0)
+	s.computes.Store(0)
+	s.computeErrors.Store(0)
+	s.evictions.Store(0)
+	s.expirations.Store(0)
+	s.computeNanos.Store(0)
+	for i := range s.computeDurations {
+		s.computeDurations[i].Store(0)
+	}
+}
+
+// Stats returns a snapshot of the counters of the cache.
+// Counters are read one at a time without stopping concurrent updates, so they may be slightly out of sync with each other.
+func (c *Cache[K, V]) Stats() Stats {
+	var st Stats
+	for i := range c.shards {
+		s := &c.shards[i].stats
+		st.Hits += s.hits.Load()
+		st.Misses += s.misses.Load()
+		st.StaleHits += s.staleHits.Load()
+		st.Computes += s.computes.Load()
+		st.ComputeErrors += s.computeErrors.Load()
+		st.Evictions += s.evictions.Load()
+		st.Expirations += s.expirations.Load()
+		st.ComputeDuration += time.Duration(s.computeNanos.Load())
+		for j := range s.computeDurations {
+			st.ComputeDurations[j] += s.computeDurations[j].Load()
+		}
+	}
+	return st
+}
+
+// ResetStats sets the counters of the cache to zero.
+func (c *Cache[K, V]) ResetStats() {
+	for i := range c.shards {
+		c.shards[i].stats.reset()
+	}
+}
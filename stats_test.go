@@ -0,0 +1,157 @@
+package go_memoize
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStatsCountsHitsAndMisses(t *testing.T) {
+	m := NewMemoizer1(func(k int) int { return k }, 0)
+	m.Call(1)
+	m.Call(1)
+	m.Call(1)
+	m.Call(2)
+	st := m.Stats()
+	if st.Hits != 2 {
+		t.Errorf("Expected 2, got %d", st.Hits)
+	}
+	if st.Misses != 2 {
+		t.Errorf("Expected 2, got %d", st.Misses)
+	}
+	if st.Computes != 2 {
+		t.Errorf("Expected 2, got %d", st.Computes)
+	}
+	if r := st.HitRatio(); r != 0.5 {
+		t.Errorf("Expected 0.5, got %v", r)
+	}
+}
+
+func TestStatsCountsComputeErrorsAndDuration(t *testing.T) {
+	cache := NewCache[int, int](0)
+	cache.GetOrComputeE(1, func() (int, error) {
+		time.Sleep(time.Millisecond)
+		return 0, errCompute
+	})
+	func() {
+		defer func() { _ = recover() }()
+		cache.GetOrCompute(2, func() int { panic("boom") })
+	}()
+	st := cache.Stats()
+	if st.Computes != 2 {
+		t.Errorf("Expected 2, got %d", st.Computes)
+	}
+	if st.ComputeErrors != 2 {
+		t.Errorf("Expected 2, got %d", st.ComputeErrors)
+	}
+	if st.ComputeDuration < time.Millisecond {
+		t.Errorf("Expected at least 1ms, got %v", st.ComputeDuration)
+	}
+}
+
+func TestStatsCountsGet(t *testing.T) {
+	cache := NewCache[int, int](0)
+	cache.Get(1)
+	cache.Set(1, 1)
+	cache.Get(1)
+	if st := cache.Stats(); st.Hits != 1 || st.Misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %d and %d", st.Hits, st.Misses)
+	}
+}
+
+func TestStatsCountsEvictionsAndExpirations(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](1, WithClock(clock), WithCapacity(10), WithShards(2))
+	for i := 0; i < 30; i++ {
+		cache.Set(i, i)
+	}
+	if st := cache.Stats(); st.Evictions != 20 {
+		t.Errorf("Expected 20, got %d", st.Evictions)
+	}
+	clock.Advance(2 * time.Second)
+	cache.sweeper.sweep()
+	if st := cache.Stats(); st.Expirations != 10 {
+		t.Errorf("Expected 10, got %d", st.Expirations)
+	}
+}
+
+func TestStatsCountsStaleHits(t *testing.T) {
+	clock := NewFakeClock()
+	cache := NewCache[int, int](1, WithClock(clock), WithStaleWhileRevalidate(time.Minute))
+	cache.Set(1, 1)
+	clock.Advance(2 * time.Second)
+	cache.GetOrCompute(1, func() int { return 2 })
+	waitFor(t, func() bool { return !inFlight(cache, 1) })
+	st := cache.Stats()
+	if st.StaleHits != 1 {
+		t.Errorf("Expected 1, got %d", st.StaleHits)
+	}
+	if r := st.HitRatio(); r != 1 {
+		t.Errorf("Expected 1, got %v", r)
+	}
+}
+
+func TestStatsReset(t *testing.T) {
+	m := NewMemoizer2(func(a, b int) int { return a + b }, 0, WithShards(4))
+	for i := 0; i < 10; i++ {
+		m.Call(i, i)
+		m.Call(i, i)
+	}
+	m.ResetStats()
+	if st := m.Stats(); st != (Stats{}) {
+		t.Errorf("Expected zero stats, got %+v", st)
+	}
+	if r := m.Stats().HitRatio(); r != 0 {
+		t.Errorf("Expected 0, got %v", r)
+	}
+}
+
+func TestStatsConcurrentAccess(t *testing.T) {
+	m := NewMemoizer1(func(k int) int { return k }, 0, WithShards(8))
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				m.Call(i % 100)
+			}
+		}()
+	}
+	wg.Wait()
+	st := m.Stats()
+	if st.Hits+st.Misses != 8000 {
+		t.Errorf("Expected 8000, got %d", st.Hits+st.Misses)
+	}
+	if st.Computes != 100 {
+		t.Errorf("Expected 100, got %d", st.Computes)
+	}
+}
+
+func TestStatsCountsComputeDurations(t *testing.T) {
+	cache := NewCache[int, int](0)
+	cache.GetOrCompute(1, func() int { return 1 })
+	cache.GetOrCompute(2, func() int {
+		time.Sleep(2 * time.Millisecond)
+		return 2
+	})
+	st := cache.Stats()
+	total := uint64(0)
+	for _, n := range st.ComputeDurations {
+		total += n
+	}
+	if total != 2 {
+		t.Errorf("Expected 2, got %d", total)
+	}
+	// the instant computation falls in a bucket of at most 1ms, the other one above
+	fast := uint64(0)
+	for i, bound := range ComputeDurationBuckets {
+		if bound > time.Millisecond {
+			break
+		}
+		fast += st.ComputeDurations[i]
+	}
+	if fast != 1 {
+		t.Errorf("Expected 1, got %d", fast)
+	}
+}
@@ -0,0 +1,162 @@
+package go_memoize
+
+// tinyLFU is the W-TinyLFU eviction policy.
+// New entries enter a window LRU holding about 1% of the capacity. Entries leaving the window become candidates
+// for the main region, a segmented LRU split into a probation and a protected segment. When the main region is
+// full, the candidate is admitted only if the frequency sketch estimates it more popular than the probation victim.
+// Entries hit while on probation are promoted to the protected segment.
+type tinyLFU[K comparable, V any] struct {
+	keyHash      func(K) uint64
+	sketch       *countMinSketch
+	window       entryList[K, V]
+	probation    entryList[K, V]
+	protected    entryList[K, V]
+	windowCap    int
+	mainCap      int
+	protectedCap int
+}
+
+// newTinyLFU creates a W-TinyLFU policy bounded to capacity entries.
+func newTinyLFU[K comparable, V any](capacity int, keyHash func(K) uint64) *tinyLFU[K, V] {
+	windowCap := capacity / 100
+	mainCap := capacity - windowCap
+	if mainCap < 1 {
+		mainCap = 1
+	}
+	p := &tinyLFU[K, V]{
+		keyHash:      keyHash,
+		sketch:       newCountMinSketch(capacity),
+		windowCap:    windowCap,
+		mainCap:      mainCap,
+		protectedCap: mainCap * 4 / 5,
+	}
+	p.window.init()
+	p.probation.init()
+	p.protected.init()
+	return p
+}
+
+func (p *tinyLFU[K, V]) add(e *entry[K, V]) *entry[K, V] {
+	p.sketch.increment(p.keyHash(e.key))
+	p.window.pushFront(e)
+	if p.window.len <= p.windowCap {
+		return nil
+	}
+
+	candidate := p.window.back()
+	p.window.remove(candidate)
+	p.probation.pushFront(candidate)
+	if p.probation.len+p.protected.len <= p.mainCap {
+		return nil
+	}
+
+	victim := p.probation.back()
+	if p.sketch.estimate(p.keyHash(candidate.key)) > p.sketch.estimate(p.keyHash(victim.key)) {
+		return victim
+	}
+	return candidate
+}
+
+func (p *tinyLFU[K, V]) replace(old, e *entry[K, V]) {
+	old.list.replace(old, e)
+	p.access(e)
+}
+
+func (p *tinyLFU[K, V]) access(e *entry[K, V]) {
+	p.sketch.increment(p.keyHash(e.key))
+	switch e.list {
+	case &p.window, &p.protected:
+		e.list.moveToFront(e)
+	case &p.probation:
+		p.probation.remove(e)
+		p.protected.pushFront(e)
+		if p.protected.len > p.protectedCap {
+			demoted := p.protected.back()
+			p.protected.remove(demoted)
+			p.probation.pushFront(demoted)
+		}
+	}
+}
+
+func (p *tinyLFU[K, V]) remove(e *entry[K, V]) {
+	e.list.remove(e)
+}
+
+func (p *tinyLFU[K, V]) len() int {
+	return p.window.len + p.probation.len + p.protected.len
+}
+
+// sketchDepth is the number of counter rows of a countMinSketch.
+const sketchDepth = 4
+
+// sketchSeeds are odd multipliers deriving an independent counter index per row from a key hash.
+var sketchSeeds = [sketchDepth]uint64{0x9e3779b97f4a7c15, 0xbf58476d1ce4e5b9, 0x94d049bb133111eb, 0xc2b2ae3d27d4eb4f}
+
+// countMinSketch estimates access frequencies with 4-bit saturating counters.
+// Counters are halved every resetAfter increments, ten times the capacity, so that the estimates favour recent popularity.
+type countMinSketch struct {
+	rows       [sketchDepth][]uint8
+	shift      uint
+	additions  int
+	resetAfter int
+}
+
+// newCountMinSketch creates a sketch sized for a cache holding capacity entries,
+// with four counters per entry and row to keep the estimation error low.
+func newCountMinSketch(capacity int) *countMinSketch {
+	width, bits := 1, uint(0)
+	for width < 4*capacity {
+		width <<= 1
+		bits++
+	}
+	s := &countMinSketch{
+		shift:      64 - bits,
+		resetAfter: 10 * capacity,
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, width)
+	}
+	return s
+}
+
+// index returns the counter index of the key hash in the given row.
+func (s *countMinSketch) index(h uint64, row int) uint64 {
+	if s.shift == 64 {
+		return 0
+	}
+	return (h * sketchSeeds[row]) >> s.shift
+}
+
+// increment records an access to the key hash.
+func (s *countMinSketch) increment(h uint64) {
+	for i := range s.rows {
+		if idx := s.index(h, i); s.rows[i][idx] < 15 {
+			s.rows[i][idx]++
+		}
+	}
+	s.additions++
+	if s.additions >= s.resetAfter {
+		s.reset()
+	}
+}
+
+// estimate returns the estimated access frequency of the key hash.
+func (s *countMinSketch) estimate(h uint64) uint8 {
+	minimum := uint8(15)
+	for i := range s.rows {
+		if c := s.rows[i][s.index(h, i)]; c < minimum {
+			minimum = c
+		}
+	}
+	return minimum
+}
+
+// reset halves all counters.
+func (s *countMinSketch) reset() {
+	for i := range s.rows {
+		for j := range s.rows[i] {
+			s.rows[i][j] >>= 1
+		}
+	}
+	s.additions /= 2
+}
@@ -0,0 +1,134 @@
+package go_memoize
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestCountMinSketch(t *testing.T) {
+	sketch := newCountMinSketch(64)
+	for i := 0; i < 5; i++ {
+		sketch.increment(hash1(FNV1a, 1))
+	}
+	sketch.increment(hash1(FNV1a, 2))
+	if e := sketch.estimate(hash1(FNV1a, 1)); e < 5 {
+		t.Errorf("Expected at least 5, got %d", e)
+	}
+	if e := sketch.estimate(hash1(FNV1a, 2)); e < 1 || e >= 5 {
+		t.Errorf("Expected an estimate between 1 and 4, got %d", e)
+	}
+	for i := 0; i < 20; i++ {
+		sketch.increment(hash1(FNV1a, 1))
+	}
+	if e := sketch.estimate(hash1(FNV1a, 1)); e != 15 {
+		t.Errorf("Expected counters to saturate at 15, got %d", e)
+	}
+	sketch.reset()
+	if e := sketch.estimate(hash1(FNV1a, 1)); e != 7 {
+		t.Errorf("Expected 7, got %d", e)
+	}
+}
+
+func TestTinyLFU_RejectsOneOffKeys(t *testing.T) {
+	cache := NewCache[int, int](0, WithCapacity(100), WithEvictionPolicy(TinyLFU))
+	for round := 0; round < 10; round++ {
+		for key := 0; key < 90; key++ {
+			cache.GetOrCompute(key, func() int { return key })
+		}
+	}
+	for key := 1000; key < 2000; key++ {
+		cache.GetOrCompute(key, func() int { return key })
+	}
+
+	kept := 0
+	for key := 0; key < 90; key++ {
+		if _, ok := cache.Get(key); ok {
+			kept++
+		}
+	}
+	if kept < 85 {
+		t.Errorf("Expected at least 85 of 90 frequently used keys to survive the scan, got %d", kept)
+	}
+}
+
+// hitRatio replays the trace against a single shard cache of the given capacity and returns the hit ratio.
+func hitRatio(policy EvictionPolicy, capacity int, trace []uint64) float64 {
+	cache := NewCache[uint64, uint64](0, WithCapacity(capacity), WithEvictionPolicy(policy))
+	hits := 0
+	for _, key := range trace {
+		if _, ok := cache.Get(key); ok {
+			hits++
+			continue
+		}
+		cache.Set(key, key)
+	}
+	return float64(hits) / float64(len(trace))
+}
+
+// zipfTrace returns n keys drawn from a Zipf distribution over keySpace keys.
+// Every scanEvery keys, a scan of scanLength never repeated keys is inserted.
+func zipfTrace(n, keySpace, scanEvery, scanLength int) []uint64 {
+	r := rand.New(rand.NewSource(42))
+	zipf := rand.NewZipf(r, 1.1, 1, uint64(keySpace-1))
+	scanKey := uint64(keySpace)
+	trace := make([]uint64, 0, n)
+	for len(trace) < n {
+		if scanEvery > 0 && len(trace)%scanEvery == 0 {
+			for i := 0; i < scanLength; i++ {
+				trace = append(trace, hash1(FNV1a, scanKey))
+				scanKey++
+			}
+		}
+		trace = append(trace, hash1(FNV1a, zipf.Uint64()))
+	}
+	return trace
+}
+
+func TestTinyLFU_HitRatioZipf(t *testing.T) {
+	trace := zipfTrace(200_000, 100_000, 0, 0)
+	lruRatio := hitRatio(LRU, 1000, trace)
+	tinyLFURatio := hitRatio(TinyLFU, 1000, trace)
+	t.Logf("zipf: LRU %.3f, TinyLFU %.3f", lruRatio, tinyLFURatio)
+	if tinyLFURatio < lruRatio {
+		t.Errorf("Expected TinyLFU hit ratio %.3f to be at least the LRU hit ratio %.3f", tinyLFURatio, lruRatio)
+	}
+}
+
+func TestTinyLFU_HitRatioZipfWithScans(t *testing.T) {
+	trace := zipfTrace(200_000, 100_000, 5_000, 2_000)
+	lruRatio := hitRatio(LRU, 1000, trace)
+	tinyLFURatio := hitRatio(TinyLFU, 1000, trace)
+	t.Logf("zipf with scans: LRU %.3f, TinyLFU %.3f", lruRatio, tinyLFURatio)
+	if tinyLFURatio < lruRatio*1.1 {
+		t.Errorf("Expected TinyLFU hit ratio %.3f to beat the LRU hit ratio %.3f by 10%%", tinyLFURatio, lruRatio)
+	}
+}
+
+func TestTinyLFU_ConcurrentAccess(t *testing.T) {
+	cache := NewCache[int, int](0, WithCapacity(64), WithShards(4), WithEvictionPolicy(TinyLFU))
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 10000; i++ {
+				key := (i * (g + 1)) % 500
+				if v := cache.GetOrCompute(key, func() int { return key * 2 }); v != key*2 {
+					t.Errorf("Expected %d, got %d", key*2, v)
+				}
+				if i%100 == 0 {
+					cache.Delete(key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	for i := range cache.shards {
+		s := &cache.shards[i]
+		if len(s.entries) > 16 || s.policy.len() != len(s.entries) {
+			t.Errorf("Expected at most 16 entries tracked by the policy, got %d entries and %d tracked", len(s.entries), s.policy.len())
+		}
+	}
+}